@@ -0,0 +1,280 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Bins describes how values are assigned to bins (see EqualWidth, Quantiles,
+// Edges and TimeBuckets)
+type Bins struct {
+	count     int           // Count of equal-width or quantile bins
+	quantiles bool          // Bins contain equal counts of values
+	edges     []float64     // Custom edges
+	bucket    time.Duration // Size of time buckets
+}
+
+// EqualWidth splits the range of numeric values into count bins of equal width
+func EqualWidth(count int) Bins {
+	return Bins{count: count}
+}
+
+// Quantiles splits numeric values into count bins containing (about) the
+// same count of values
+func Quantiles(count int) Bins {
+	return Bins{count: count, quantiles: true}
+}
+
+// Edges splits numeric values into bins between consecutive edges. Values
+// outside of the edges are not assigned to any bin.
+func Edges(edges ...float64) Bins {
+	return Bins{edges: edges}
+}
+
+// TimeBuckets assigns time.Times to buckets of the size, e.g. time.Hour. The
+// times are bucketed in their own locations, e.g. day buckets start at the
+// local midnight.
+func TimeBuckets(size time.Duration) Bins {
+	return Bins{bucket: size}
+}
+
+// bin is a resolved bin
+type bin struct {
+	label        string
+	lower, upper float64   // Range of numeric bins
+	start        time.Time // Start of time buckets (wall clock)
+}
+
+// resolve calculates the bins of the values and returns a function assigning
+// values to the bins (-1 = no bin)
+func (b Bins) resolve(values []interface{}) ([]bin, func(v interface{}) int, error) {
+
+	if b.bucket != 0 {
+		return b.resolveTime(values)
+	}
+
+	numbers := []float64{}
+	for _, v := range values {
+		if f, ok := toFloat(v); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			numbers = append(numbers, f)
+		}
+	}
+	sort.Float64s(numbers)
+
+	// Edges
+	edges := []float64{}
+	switch {
+	case len(b.edges) > 0:
+		edges = append(edges, b.edges...)
+		sort.Float64s(edges)
+	case b.count < 1:
+		return nil, nil, fmt.Errorf("the count of bins must be positive")
+	case len(numbers) == 0:
+	case b.quantiles:
+		for i := 0; i <= b.count; i++ {
+			edges = append(edges, quantile(numbers, float64(i)/float64(b.count)))
+		}
+	default:
+		low, high := numbers[0], numbers[len(numbers)-1]
+		for i := 0; i <= b.count; i++ {
+			edges = append(edges, low+(high-low)*float64(i)/float64(b.count))
+		}
+	}
+
+	// Duplicate edges would result in empty bins
+	unique := []float64{}
+	for i, edge := range edges {
+		if i == 0 || edge != edges[i-1] {
+			unique = append(unique, edge)
+		}
+	}
+	if len(unique) == 1 {
+		unique = append(unique, unique[0])
+	}
+
+	// The last bin includes its upper edge
+	bins := []bin{}
+	for i := 0; i+1 < len(unique); i++ {
+		label := fmt.Sprintf("[%s, %s)", shortFloat(unique[i]), shortFloat(unique[i+1]))
+		if i+2 == len(unique) {
+			label = fmt.Sprintf("[%s, %s]", shortFloat(unique[i]), shortFloat(unique[i+1]))
+		}
+		bins = append(bins, bin{label: label, lower: unique[i], upper: unique[i+1]})
+	}
+
+	assign := func(v interface{}) int {
+		f, ok := toFloat(v)
+		if !ok {
+			return -1
+		}
+		for i, bin := range bins {
+			if f >= bin.lower && (f < bin.upper || (i == len(bins)-1 && f == bin.upper)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	return bins, assign, nil
+}
+
+// resolveTime calculates the time buckets between the earliest and the latest
+// time
+func (b Bins) resolveTime(values []interface{}) ([]bin, func(v interface{}) int, error) {
+
+	if b.bucket < 0 {
+		return nil, nil, fmt.Errorf("the size of time buckets must be positive")
+	}
+
+	layout := "2006-01-02 15:04"
+	switch {
+	case b.bucket%(24*time.Hour) == 0:
+		layout = "2006-01-02"
+	case b.bucket < time.Minute:
+		layout = "2006-01-02 15:04:05"
+	}
+
+	var first, last time.Time
+	for _, v := range values {
+		if timestamp, ok := toTime(v); ok {
+			start := wallClock(timestamp).Truncate(b.bucket)
+			if first.IsZero() || start.Before(first) {
+				first = start
+			}
+			if last.IsZero() || start.After(last) {
+				last = start
+			}
+		}
+	}
+
+	bins := []bin{}
+	for start := first; !first.IsZero() && !start.After(last); start = start.Add(b.bucket) {
+		bins = append(bins, bin{label: start.Format(layout), start: start})
+	}
+
+	assign := func(v interface{}) int {
+		timestamp, ok := toTime(v)
+		if !ok || len(bins) == 0 {
+			return -1
+		}
+		return int(wallClock(timestamp).Truncate(b.bucket).Sub(first) / b.bucket)
+	}
+
+	return bins, assign, nil
+}
+
+// wallClock returns the wall clock of the time in its own location as a UTC
+// time, so that buckets are truncated in the location (e.g. day buckets start
+// at the local midnight) and are not affected by daylight saving time
+func wallClock(timestamp time.Time) time.Time {
+	year, month, day := timestamp.Date()
+	hour, min, sec := timestamp.Clock()
+	return time.Date(year, month, day, hour, min, sec, timestamp.Nanosecond(), time.UTC)
+}
+
+// Bin adds a categorical column (name) assigning the body rows to bins of the
+// values of a column. Bins are labeled by their ranges (e.g. "[0, 10)") or
+// start times, unless labels are provided. Rows whose values do not fall into
+// any bin have missing values.
+// NB: locks t, then the header and the body rows
+func (t *table) Bin(name, column string, bins Bins, labels ...string) error {
+
+	writes, err := t.binColumn(name, column, bins, labels...)
+	if err != nil {
+		return fmt.Errorf("Bin: %s", err.Error())
+	}
+	writeCells(writes)
+
+	return nil
+}
+
+// binColumn returns the header and the body row bins of the bin column
+// NB: locks t
+func (t *table) binColumn(name, column string, bins Bins, labels ...string) ([]cellWrite, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil, fmt.Errorf("table has no header")
+	}
+
+	if t.getColnameIndex(name, false, false) != -1 {
+		return nil, fmt.Errorf("column '%s' already exists", name)
+	}
+
+	colIdx := t.getColIdx(column)
+	if len(colIdx) == 0 {
+		return nil, fmt.Errorf("no such column '%s'", column)
+	}
+
+	body := t.bodyRows()
+	values := make([]interface{}, len(body))
+	for i, row := range body {
+		values[i] = cellValue(row, colIdx[0])
+	}
+
+	resolved, assign, err := bins.resolve(values)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) > 0 && len(labels) != len(resolved) {
+		return nil, fmt.Errorf("expected %d labels, got %d", len(resolved), len(labels))
+	}
+
+	// Add the column (the header is written with the body rows)
+	position := t.columnCount()
+	writes := []cellWrite{{header, position, name, false}}
+	for i, row := range body {
+		value := interface{}(Missing)
+		if k := assign(values[i]); k != -1 {
+			value = resolved[k].label
+			if len(labels) > 0 {
+				value = labels[k]
+			}
+		}
+		writes = append(writes, cellWrite{row, position, value, false})
+	}
+
+	return writes, nil
+}
+
+// Histogram returns a table of the bins of a column's values, with the count
+// of body rows in every bin and a bar chart of the counts (modified view only)
+// NB: locks t
+func (t *table) Histogram(column string, bins Bins) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	colIdx := t.getColIdx(column)
+	if len(colIdx) == 0 {
+		return nil, fmt.Errorf("Histogram: no such column '%s'", column)
+	}
+
+	values := []interface{}{}
+	for _, row := range t.bodyRows() {
+		values = append(values, cellValue(row, colIdx[0]))
+	}
+
+	resolved, assign, err := bins.resolve(values)
+	if err != nil {
+		return nil, fmt.Errorf("Histogram: %s", err.Error())
+	}
+
+	counts := make([]int, len(resolved))
+	for _, v := range values {
+		if k := assign(v); k != -1 {
+			counts[k]++
+		}
+	}
+
+	histogram := New(fmt.Sprintf("%v", cellValue(t.headAndFoot["header"], colIdx[0])), "Count", "Histogram")
+	for k, bin := range resolved {
+		histogram.AddRow("").Insert(bin.label, counts[k], counts[k])
+	}
+	histogram.SetCellRenderer(BarRenderer(20), "Histogram")
+
+	return histogram, nil
+}
@@ -0,0 +1,89 @@
+package lentele
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBin(t *testing.T) {
+
+	table := New("Latency")
+	for _, latency := range []interface{}{12, 40.5, 7, 95, Missing, "timeout"} {
+		table.AddRow("").Insert(latency)
+	}
+
+	if err := table.Bin("Speed", "Latency", Edges(0, 20, 100), "fast"); err == nil {
+		t.Errorf("TestBin: the count of labels should match the count of bins")
+	}
+	if err := table.Bin("Speed", "Latency", EqualWidth(0)); err == nil {
+		t.Errorf("TestBin: the count of bins must be positive")
+	}
+	if err := table.Bin("Latency", "Latency", EqualWidth(2)); err == nil {
+		t.Errorf("TestBin: existing columns should fail")
+	}
+
+	// Values outside of the bins and non-numeric values are missing
+	table.Bin("Speed", "Latency", Edges(0, 20, 50), "fast", "slow")
+	checkValues(t, "TestBin", table, "Speed", "fast", "slow", "fast", Missing, Missing, Missing)
+
+	// The last bin includes the maximum
+	table.Bin("Range", "Latency", EqualWidth(2))
+	checkValues(t, "TestBin", table, "Range", "[7, 51)", "[7, 51)", "[7, 51)", "[51, 95]", Missing, Missing)
+
+	// Quantiles split the values into bins of equal counts
+	table.Bin("Half", "Latency", Quantiles(2), "low", "high")
+	checkValues(t, "TestBin", table, "Half", "low", "high", "low", "high", Missing, Missing)
+}
+
+func TestTimeBuckets(t *testing.T) {
+
+	start := time.Date(2024, 3, 15, 10, 50, 0, 0, time.UTC)
+	table := New("Time")
+	table.AddRow("").Insert(start)
+	table.AddRow("").Insert(start.Add(10 * time.Minute))
+	table.AddRow("").Insert(start.Add(75 * time.Minute))
+
+	table.Bin("Hour", "Time", TimeBuckets(time.Hour))
+	checkValues(t, "TestTimeBuckets", table, "Hour", "2024-03-15 10:00", "2024-03-15 11:00", "2024-03-15 12:00")
+
+	// Days are labeled without the time of day
+	table.Bin("Day", "Time", TimeBuckets(24*time.Hour))
+	checkValues(t, "TestTimeBuckets", table, "Day", "2024-03-15", "2024-03-15", "2024-03-15")
+}
+
+func TestTimeBucketLocations(t *testing.T) {
+
+	cest := time.FixedZone("CEST", 2*60*60)
+	table := New("Time")
+	table.AddRow("").Insert(time.Date(2024, 1, 2, 1, 0, 0, 0, cest))
+	table.AddRow("").Insert(time.Date(2024, 1, 3, 23, 0, 0, 0, cest))
+
+	// Days start at the local midnight, not at the UTC one
+	table.Bin("Day", "Time", TimeBuckets(24*time.Hour))
+	checkValues(t, "TestTimeBucketLocations", table, "Day", "2024-01-02", "2024-01-03")
+}
+
+func TestHistogram(t *testing.T) {
+
+	table := New("Latency")
+	for _, latency := range []interface{}{12, 40, 7, 95, 33, 18, Missing} {
+		table.AddRow("").Insert(latency)
+	}
+
+	if _, err := table.Histogram("Size", EqualWidth(3)); err == nil {
+		t.Errorf("TestHistogram: unknown columns should fail")
+	}
+
+	histogram, err := table.Histogram("Latency", Edges(0, 25, 50, 100))
+	if err != nil {
+		t.Fatalf("TestHistogram: could not build histogram: %s", err.Error())
+	}
+	checkValues(t, "TestHistogram", histogram, "Latency", "[0, 25)", "[25, 50)", "[50, 100]")
+	checkValues(t, "TestHistogram", histogram, "Count", 3, 2, 1)
+
+	// The largest count fills the whole bar
+	if rendered := renderMySQL(histogram); !strings.Contains(rendered, "|   3   | "+strings.Repeat("█", 20)+" |") {
+		t.Errorf("TestHistogram: unexpected bars:\n%s", rendered)
+	}
+}
@@ -0,0 +1,247 @@
+package lentele
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NameConflict decides what happens to appended rows whose names are already
+// used (see Table.Append and Concat)
+type NameConflict int
+
+// Treatments of conflicting row names
+const (
+	NameError     NameConflict = iota // Fail without appending anything
+	NameSuffix                        // Suffix the name, e.g. "web1_2"
+	NameOverwrite                     // Replace the existing row
+)
+
+// appended is a snapshot of the appended table
+type appended struct {
+	columns   []string
+	rows      []*row
+	names     []string
+	titles    []string
+	footnotes []string
+}
+
+// Append appends the body rows of another table, aligning the columns by their
+// names in the headers. Columns absent in t are added to its header, absent
+// columns of the appended rows are missing. The footer of the other table is
+// dropped, its titles and footnotes are added unless t already has them. The
+// rows are copied (without merged cells).
+// NB: locks other, then t (and the header)
+func (t *table) Append(other Table, conflict NameConflict) error {
+
+	source, ok := other.(*table)
+	if !ok {
+		return fmt.Errorf("Append: unsupported table implementation")
+	}
+
+	snapshot, err := source.snapshot()
+	if err != nil {
+		return fmt.Errorf("Append: %s", err.Error())
+	}
+
+	t.Lock()
+	writes, err := t.append(snapshot, conflict)
+	t.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("Append: %s", err.Error())
+	}
+
+	// The header is locked after t (same as in the row methods)
+	writeCells(writes)
+
+	return nil
+}
+
+// Concat creates a new table of the body rows of the tables (see Append). The
+// new table has no footer.
+func Concat(conflict NameConflict, tables ...Table) (Table, error) {
+
+	concatenated := New().(*table)
+	for _, other := range tables {
+		if err := concatenated.Append(other, conflict); err != nil {
+			return nil, fmt.Errorf("Concat: %s", err.Error())
+		}
+	}
+
+	return concatenated, nil
+}
+
+// snapshot copies the header names and the body rows of a table
+// NB: locks t
+func (t *table) snapshot() (*appended, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil, fmt.Errorf("table has no header")
+	}
+
+	snapshot := &appended{
+		titles:    append([]string{}, t.Titles...),
+		footnotes: append([]string{}, t.Footnotes...),
+	}
+	for _, c := range header.Cells {
+		snapshot.columns = append(snapshot.columns, fmt.Sprintf("%v", c.Value))
+	}
+
+	copies := map[*row]*row{}
+	for i, r := range t.Rows {
+		if r == header || r == t.headAndFoot["footer"] {
+			continue
+		}
+		copies[r] = copyRow(r, nil)
+		snapshot.rows = append(snapshot.rows, copies[r])
+		snapshot.names = append(snapshot.names, t.RowNames[i])
+	}
+
+	// Parents outside of the body are dropped
+	for _, copied := range snapshot.rows {
+		copied.parent = copies[copied.parent]
+	}
+
+	return snapshot, nil
+}
+
+// append appends the snapshot's rows to t and returns the header writes of the
+// added columns (see alignColumns)
+func (t *table) append(snapshot *appended, conflict NameConflict) ([]cellWrite, error) {
+
+	// Body rows by their names
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+	used := map[string]int{}
+	for i, name := range t.RowNames {
+		if _, ok := used[name]; !ok && name != "" && t.Rows[i] != header && t.Rows[i] != footer {
+			used[name] = i
+		}
+	}
+
+	// Reserved names are conflicts as well
+	switch conflict {
+	case NameError:
+		seen := map[string]bool{}
+		for _, name := range snapshot.names {
+			if _, ok := used[name]; (ok || seen[name] || isReserved(name)) && name != "" {
+				return nil, fmt.Errorf("row name '%s' already exists", name)
+			}
+			seen[name] = true
+		}
+	case NameSuffix, NameOverwrite:
+	default:
+		return nil, fmt.Errorf("unknown name conflict treatment %d", conflict)
+	}
+
+	// Align the columns
+	position, writes, err := t.alignColumns(snapshot.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Append (or overwrite) the rows
+	for i, copied := range snapshot.rows {
+		aligned := []*cell{}
+		for j, c := range copied.Cells {
+			if j >= len(position) {
+				break
+			}
+			for len(aligned) <= position[j] {
+				aligned = append(aligned, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+			}
+			c.ColSpan, c.RowSpan = 0, 0
+			aligned[position[j]] = c
+		}
+		copied.Cells = aligned
+		copied.tref = t
+
+		name := snapshot.names[i]
+		if k, ok := used[name]; (ok || isReserved(name)) && name != "" {
+			if conflict == NameOverwrite && ok {
+				t.Rows[k] = copied
+				continue
+			}
+			for n := 2; ; n++ {
+				suffixed := fmt.Sprintf("%s_%d", name, n)
+				if _, taken := used[suffixed]; !taken {
+					name = suffixed
+					break
+				}
+			}
+		}
+
+		if name != "" {
+			used[name] = len(t.Rows)
+		}
+		t.Rows = append(t.Rows, copied)
+		t.RowNames = append(t.RowNames, name)
+	}
+
+	t.restructure()
+
+	// Titles and footnotes
+	t.Titles = appendNew(t.Titles, snapshot.titles)
+	t.Footnotes = appendNew(t.Footnotes, snapshot.footnotes)
+
+	return writes, nil
+}
+
+// alignColumns returns the positions of the columns in t, adding the header to
+// empty tables. The names of the absent columns have to be written into the
+// header after unlocking t (see writeCells).
+func (t *table) alignColumns(columns []string) ([]int, []cellWrite, error) {
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		if len(t.Rows) > 0 {
+			return nil, nil, fmt.Errorf("table has no header")
+		}
+		header = &row{Mutex: &sync.Mutex{}, Cells: []*cell{}, tref: t}
+		t.Rows = append(t.Rows, header)
+		t.RowNames = append(t.RowNames, "header")
+		t.headAndFoot["header"] = header
+		t.restructure()
+	}
+
+	// Absent columns are added once (names are case-insensitive)
+	position := make([]int, len(columns))
+	added := map[string]int{}
+	writes := []cellWrite{}
+	for j, colname := range columns {
+		position[j] = t.getColnameIndex(colname, false, false)
+		if position[j] != -1 {
+			continue
+		}
+		if col, ok := added[strings.ToLower(colname)]; ok {
+			position[j] = col
+			continue
+		}
+		position[j] = t.columnCount() + len(writes)
+		added[strings.ToLower(colname)] = position[j]
+		writes = append(writes, cellWrite{header, position[j], colname, false})
+	}
+
+	return position, writes, nil
+}
+
+// appendNew appends the strings absent in dst
+func appendNew(dst, src []string) []string {
+	for _, s := range src {
+		found := false
+		for _, d := range dst {
+			if s == d {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst = append(dst, s)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,111 @@
+package lentele
+
+import (
+	"strings"
+	"testing"
+)
+
+// buildShards builds tables of two shards with differently ordered columns
+func buildShards() (Table, Table) {
+	first := New("Host", "Load")
+	first.AddTitle("Shard load")
+	first.AddRow("web1").Insert("web1", 1)
+	first.AddRow("web2").Insert("web2", 2)
+	first.AddFooter().Insert("Total", 3)
+
+	second := New("Region", "host", "Load")
+	second.AddTitle("Shard load")
+	second.AddFootnote("Second shard")
+	second.AddRow("web2").Insert("eu", "web2", 5)
+	second.AddRow("").Insert("us", "web3", 7)
+	second.AddFooter().Insert("", "Total", 12)
+
+	return first, second
+}
+
+func TestAppend(t *testing.T) {
+
+	first, second := buildShards()
+	if err := first.Append(second, NameError); err == nil {
+		t.Errorf("TestAppend: conflicting row names should fail")
+	}
+	if first.GetRowCount() != 4 {
+		t.Errorf("TestAppend: failed appends should not change the table")
+	}
+
+	if err := first.Append(second, NameSuffix); err != nil {
+		t.Fatalf("TestAppend: could not append: %s", err.Error())
+	}
+
+	// Columns are aligned by their names, absent columns are added
+	checkValues(t, "TestAppend", first, "Host", "web1", "web2", "web2", "web3")
+	checkValues(t, "TestAppend", first, "Region", Missing, Missing, "eu", "us")
+
+	// Titles are not duplicated, the appended footer is dropped
+	if impl := original(first); len(impl.Titles) != 1 || len(impl.Footnotes) != 1 {
+		t.Errorf("TestAppend: unexpected titles %v or footnotes %v", impl.Titles, impl.Footnotes)
+	}
+
+	names := first.GetRowNames()
+	if strings.Join(names, ",") != "header,web1,web2,footer,web2_2," {
+		t.Errorf("TestAppend: unexpected row names %v", names)
+	}
+
+	// Overwrite
+	first, second = buildShards()
+	if err := first.Append(second, NameOverwrite); err != nil {
+		t.Fatalf("TestAppend: could not append: %s", err.Error())
+	}
+	if web2, _ := first.GetRowByName("web2"); cellValue(web2.(*row), 1) != 5 {
+		t.Errorf("TestAppend: the row should be overwritten")
+	}
+	if first.GetRowCount() != 5 {
+		t.Errorf("TestAppend: expected 5 rows, got %d", first.GetRowCount())
+	}
+}
+
+func TestAppendReservedNames(t *testing.T) {
+
+	first, second := buildShards()
+	original(second).RowNames[2] = "header"
+
+	if err := first.Append(second, NameError); err == nil {
+		t.Errorf("TestAppendReservedNames: reserved row names should fail")
+	}
+
+	// Reserved names are suffixed instead of replacing the header
+	if err := first.Append(second, NameOverwrite); err != nil {
+		t.Fatalf("TestAppendReservedNames: could not append: %s", err.Error())
+	}
+	if names := strings.Join(first.GetRowNames(), ","); names != "header,web1,web2,footer,header_2" {
+		t.Errorf("TestAppendReservedNames: unexpected row names %s", names)
+	}
+	if header := original(first).headAndFoot["header"]; cellValue(header, 0) != "Host" {
+		t.Errorf("TestAppendReservedNames: the header should be kept")
+	}
+}
+
+func TestConcat(t *testing.T) {
+
+	first, second := buildShards()
+	if _, err := Concat(NameSuffix, first, New()); err == nil {
+		t.Errorf("TestConcat: tables without a header should fail")
+	}
+
+	concatenated, err := Concat(NameSuffix, second, first)
+	if err != nil {
+		t.Fatalf("TestConcat: could not concatenate: %s", err.Error())
+	}
+
+	// The columns of the first table come first, the footers are dropped
+	checkValues(t, "TestConcat", concatenated, "Region", "eu", "us", Missing, Missing)
+	checkValues(t, "TestConcat", concatenated, "Host", "web2", "web3", "web1", "web2")
+	if _, err := concatenated.GetRowByName("footer"); err == nil {
+		t.Errorf("TestConcat: concatenated tables should have no footer")
+	}
+
+	// The inputs are not changed
+	if first.GetRowCount() != 4 || second.GetRowCount() != 4 {
+		t.Errorf("TestConcat: the inputs should not change")
+	}
+}
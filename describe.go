@@ -0,0 +1,179 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Statistics of numeric and categorical columns (see Describe)
+var (
+	numericStatistics     = []string{"count", "missing", "mean", "std", "min", "25%", "50%", "75%", "max"}
+	categoricalStatistics = []string{"count", "missing", "unique", "top", "freq"}
+)
+
+// Describe returns a table summarising the body rows of the columns (all the
+// columns if none are provided). Numeric columns are described by the count
+// of values, the count of missing (or nil) values, the mean, the (sample)
+// standard deviation, the minimum, the quartiles and the maximum. Other
+// columns are described by the count of values, the count of missing values,
+// the count of unique values and the most frequent value with its frequency.
+// Statistics are rows named after the statistic, e.g. "mean".
+// NB: locks t
+func (t *table) Describe(colnames ...string) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil, fmt.Errorf("Describe: table has no header")
+	}
+
+	colIdx, err := t.missingColumns(colnames...)
+	if err != nil {
+		return nil, fmt.Errorf("Describe: %s", err.Error())
+	}
+
+	// Describe the columns
+	body := t.bodyRows()
+	columns := []string{""}
+	described := []map[string]interface{}{}
+	numeric, categorical := false, false
+	for _, col := range colIdx {
+
+		values := []interface{}{}
+		for _, row := range body {
+			if v := cellValue(row, col); v != nil && !isMissing(v) {
+				values = append(values, v)
+			}
+		}
+
+		stats := describeNumeric(values)
+		if stats != nil {
+			numeric = true
+		} else {
+			stats = describeCategorical(values)
+			categorical = true
+		}
+		stats["count"] = len(values)
+		stats["missing"] = len(body) - len(values)
+
+		columns = append(columns, fmt.Sprintf("%v", cellValue(header, col)))
+		described = append(described, stats)
+	}
+
+	// Statistics of all the described column kinds
+	statistics := []string{}
+	if numeric {
+		statistics = append(statistics, numericStatistics...)
+	}
+	if categorical {
+		for _, stat := range categoricalStatistics {
+			if !numeric || (stat != "count" && stat != "missing") {
+				statistics = append(statistics, stat)
+			}
+		}
+	}
+
+	summary := New(columns...)
+	for _, stat := range statistics {
+		values := []interface{}{stat}
+		for _, stats := range described {
+			if value, ok := stats[stat]; ok {
+				values = append(values, value)
+			} else {
+				values = append(values, Missing)
+			}
+		}
+		summary.AddRow(stat).Insert(values...)
+	}
+
+	// Statistics are rounded to 6 significant digits
+	summary.AddTypeFormat(&TypeFormat{Match: MatchType(0.0), Formatter: func(v interface{}) (string, bool) {
+		return shortFloat(v.(float64)), true
+	}})
+
+	return summary, nil
+}
+
+// describeNumeric calculates the statistics of numeric values. Returns nil if
+// some of the values are not numeric.
+func describeNumeric(values []interface{}) map[string]interface{} {
+
+	numbers := make([]float64, 0, len(values))
+	for _, v := range values {
+		f, ok := toFloat(v)
+		if !ok {
+			return nil
+		}
+		numbers = append(numbers, f)
+	}
+
+	stats := map[string]interface{}{}
+	if len(numbers) == 0 {
+		return stats
+	}
+
+	sort.Float64s(numbers)
+
+	mean := 0.0
+	for _, f := range numbers {
+		mean += f / float64(len(numbers))
+	}
+	stats["mean"] = mean
+
+	if len(numbers) > 1 {
+		variance := 0.0
+		for _, f := range numbers {
+			variance += (f - mean) * (f - mean)
+		}
+		stats["std"] = math.Sqrt(variance / float64(len(numbers)-1))
+	}
+
+	stats["min"] = numbers[0]
+	stats["25%"] = quantile(numbers, 0.25)
+	stats["50%"] = quantile(numbers, 0.5)
+	stats["75%"] = quantile(numbers, 0.75)
+	stats["max"] = numbers[len(numbers)-1]
+
+	return stats
+}
+
+// describeCategorical calculates the statistics of categorical values
+func describeCategorical(values []interface{}) map[string]interface{} {
+
+	// The first of equally frequent values is the top value
+	counts := map[string]int{}
+	top, freq := "", 0
+	for _, v := range values {
+		key := fmt.Sprintf("%v", v)
+		counts[key]++
+		if counts[key] > freq {
+			top, freq = key, counts[key]
+		}
+	}
+
+	stats := map[string]interface{}{"unique": len(counts)}
+	if len(values) > 0 {
+		stats["top"] = top
+		stats["freq"] = freq
+	}
+
+	return stats
+}
+
+// quantile returns the linearly interpolated quantile of sorted numbers
+func quantile(sorted []float64, q float64) float64 {
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// shortFloat formats a float rounded to 6 significant digits without trailing
+// zeros
+func shortFloat(f float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'e', 5, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
@@ -0,0 +1,42 @@
+package lentele
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+
+	table := New("Endpoint", "Latency", "Status")
+	table.AddRow("").Insert("/users", 12, "ok")
+	table.AddRow("").Insert("/users", 20, "ok")
+	table.AddRow("").Insert("/orders", 31.5, "error")
+	table.AddRow("").Insert("/orders", Missing, "ok")
+	table.AddRow("").Insert("/health", 2, nil)
+	table.AddFooter().Insert("Total", 1000)
+
+	if _, err := table.Describe("Size"); err == nil {
+		t.Errorf("TestDescribe: unknown columns should fail")
+	}
+
+	summary, err := table.Describe("Latency", "Status")
+	if err != nil {
+		t.Fatalf("TestDescribe: could not describe table: %s", err.Error())
+	}
+
+	// Statistics are rows, numeric statistics are skipped for other columns
+	names := summary.GetRowNames()
+	if strings.Join(names, ",") != "header,count,missing,mean,std,min,25%,50%,75%,max,unique,top,freq" {
+		t.Errorf("TestDescribe: unexpected statistics %v", names)
+	}
+	checkValues(t, "TestDescribe", summary, "Status", 4, 1, Missing, Missing, Missing, Missing, Missing, Missing, Missing, 2, "ok", 3)
+
+	// Statistics are raw values, the footer is skipped
+	for i, expected := range []float64{4, 1, 16.375, 12.4858, 2, 9.5, 16, 22.875, 31.5} {
+		value, _ := toFloat(columnValues(summary, "Latency")[i])
+		if math.Abs(value-expected) > 1e-4 {
+			t.Errorf("TestDescribe: expected %s of %v, got %v", names[i+1], expected, value)
+		}
+	}
+}
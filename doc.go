@@ -34,6 +34,12 @@
 // to color), but increase the size of the string. Use "measureModified=false"
 // when rendering tables that include ansi colors.
 //
+// Themes
+//
+// Borders, headers, footers, titles, footnotes, rows and columns can also be
+// colored by setting a Theme on the Template. Themes are applied after the
+// cell widths have been calculated and do not require measureModified=false.
+//
 // Mixing ansi and non-ansi modifiers
 //
 // Mixing regular modifiers (e.g. insertion of thousands-separators)
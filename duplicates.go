@@ -0,0 +1,218 @@
+package lentele
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Keep selects the row kept of duplicate rows (see Table.DropDuplicates)
+type Keep int
+
+// Rows kept of duplicate rows
+const (
+	KeepFirst Keep = iota // Keep the first row
+	KeepLast              // Keep the last row
+	KeepNone              // Drop all the duplicate rows
+)
+
+// DropDuplicates removes the body rows whose values of the columns (all the
+// columns if none are provided) are deeply equal to the values of another
+// row, keeping the first, the last or none of them. If count is provided, a
+// column with the count of collapsed duplicates is added. The rows are
+// removed inplace or a new table, referencing the kept rows, is created (same
+// as Filter). A new table with a count column contains copies of the rows.
+// NB: locks t (then the header and the body rows if the count is added inplace)
+func (t *table) DropDuplicates(keep Keep, count string, inplace, keepFooter bool, colnames ...string) (Table, error) {
+
+	fTable, writes, err := t.dropDuplicates(keep, count, inplace, keepFooter, colnames...)
+	if err != nil {
+		return nil, fmt.Errorf("DropDuplicates: %s", err.Error())
+	}
+	writeCells(writes)
+
+	return fTable, nil
+}
+
+// dropDuplicates removes the duplicate rows and returns the header and the
+// counts of the rows kept inplace (the rows of a new table are counted right
+// away)
+// NB: locks t
+func (t *table) dropDuplicates(keep Keep, count string, inplace, keepFooter bool, colnames ...string) (Table, []cellWrite, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	colIdx, err := t.missingColumns(colnames...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if count != "" {
+		if _, ok := t.headAndFoot["header"]; !ok {
+			return nil, nil, fmt.Errorf("count column requires a header")
+		}
+		if t.getColnameIndex(count, false, false) != -1 {
+			return nil, nil, fmt.Errorf("column '%s' already exists", count)
+		}
+	}
+
+	// Kept rows
+	matching := map[*row]bool{}
+	counts := map[*row]int{}
+	for _, group := range duplicateGroups(t.bodyRows(), colIdx) {
+		var kept *row
+		switch keep {
+		case KeepFirst:
+			kept = group[0]
+		case KeepLast:
+			kept = group[len(group)-1]
+		case KeepNone:
+			if len(group) == 1 {
+				kept = group[0]
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown keep option %d", keep)
+		}
+		if kept != nil {
+			matching[kept] = true
+			counts[kept] = len(group)
+		}
+	}
+
+	fTable := t.selectRows(matching, inplace, keepFooter)
+	if count == "" {
+		return fTable, nil, nil
+	}
+
+	// Rows referenced by both tables cannot get a new column
+	if !inplace {
+		copies := map[*row]*row{}
+		for i, original := range fTable.Rows {
+			copies[original] = copyRow(original, fTable)
+			fTable.Rows[i] = copies[original]
+			counts[copies[original]] = counts[original]
+		}
+		for _, copied := range fTable.Rows {
+			if parent, ok := copies[copied.parent]; ok {
+				copied.parent = parent
+			}
+		}
+		for name, special := range fTable.headAndFoot {
+			fTable.headAndFoot[name] = copies[special]
+		}
+		fTable.restructure()
+	}
+
+	// Copies are owned by the new table, the header and the rows kept inplace
+	// are locked later
+	position := fTable.columnCount()
+	writes := []cellWrite{}
+	for _, kept := range fTable.Rows {
+		value := interface{}(counts[kept])
+		switch kept {
+		case fTable.headAndFoot["header"]:
+			value = count
+		case fTable.headAndFoot["footer"]:
+			continue
+		}
+		if inplace {
+			writes = append(writes, cellWrite{kept, position, value, false})
+		} else {
+			setCell(kept, position, value)
+		}
+	}
+
+	return fTable, writes, nil
+}
+
+// Distinct returns a new table of the distinct (deeply equal) combinations of
+// the values of the columns (all the columns if none are provided) in the
+// order of their first appearance
+// NB: locks t
+func (t *table) Distinct(colnames ...string) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil, fmt.Errorf("Distinct: table has no header")
+	}
+
+	colIdx, err := t.missingColumns(colnames...)
+	if err != nil {
+		return nil, fmt.Errorf("Distinct: %s", err.Error())
+	}
+
+	columns := []string{}
+	for _, col := range colIdx {
+		columns = append(columns, fmt.Sprintf("%v", cellValue(header, col)))
+	}
+
+	distinct := New(columns...)
+	for _, group := range duplicateGroups(t.bodyRows(), colIdx) {
+		distinct.AddRow("").Insert(rowValues(group[0], colIdx)...)
+	}
+
+	return distinct, nil
+}
+
+// duplicateGroups groups rows whose values of the columns are deeply equal
+// (in the order of their first appearance)
+func duplicateGroups(rows []*row, colIdx []int) [][]*row {
+
+	groups := [][]*row{}
+	buckets := map[string][]int{} // Groups by the printed values
+	for _, r := range rows {
+		values := rowValues(r, colIdx)
+		key := fmt.Sprintf("%#v", values)
+
+		found := false
+		for _, g := range buckets[key] {
+			if reflect.DeepEqual(rowValues(groups[g][0], colIdx), values) {
+				groups[g] = append(groups[g], r)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			buckets[key] = append(buckets[key], len(groups))
+			groups = append(groups, []*row{r})
+		}
+	}
+
+	return groups
+}
+
+// rowValues returns the values of the row's columns
+func rowValues(r *row, colIdx []int) []interface{} {
+	values := make([]interface{}, len(colIdx))
+	for j, col := range colIdx {
+		values[j] = cellValue(r, col)
+	}
+	return values
+}
+
+// copyRow copies a row (and its cells) into a table. The row is owned by the
+// caller's (locked) table and is not locked, since the row methods lock the
+// row before the table.
+func copyRow(r *row, t *table) *row {
+	copied := &row{
+		Mutex:  &sync.Mutex{},
+		Cells:  make([]*cell, len(r.Cells)),
+		tref:   t,
+		parent: r.parent,
+	}
+	for j, c := range r.Cells {
+		copied.Cells[j] = &cell{
+			Mutex:   &sync.Mutex{},
+			Value:   c.Value,
+			ModVal:  c.ModVal,
+			ColSpan: c.ColSpan,
+			RowSpan: c.RowSpan,
+			modFunc: c.modFunc,
+		}
+	}
+
+	return copied
+}
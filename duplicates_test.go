@@ -0,0 +1,98 @@
+package lentele
+
+import (
+	"testing"
+)
+
+func TestDropDuplicates(t *testing.T) {
+
+	table := New("Host", "Tags", "Load")
+	table.AddRow("").Insert("web1", []string{"a", "b"}, 1)
+	table.AddRow("").Insert("web2", []string{"a"}, 2)
+	table.AddRow("").Insert("web1", []string{"a", "b"}, 3)
+	table.AddRow("").Insert("web1", []string{"a", "b"}, 1)
+	table.AddRow("").Insert("web3", []interface{}{"a", "b"}, 1)
+
+	if _, err := table.DropDuplicates(KeepFirst, "", false, true, "Unknown"); err == nil {
+		t.Errorf("TestDropDuplicates: unknown columns should fail")
+	}
+	if _, err := table.DropDuplicates(KeepFirst, "Load", false, true); err == nil {
+		t.Errorf("TestDropDuplicates: existing count column should fail")
+	}
+
+	// Slices are compared deeply, slices of different types are not equal
+	tests := []struct {
+		keep     Keep
+		colnames []string
+		loads    []interface{}
+	}{
+		{KeepFirst, nil, []interface{}{1, 2, 3, 1}},
+		{KeepLast, []string{"Host", "Tags"}, []interface{}{2, 1, 1}},
+		{KeepNone, []string{"Host", "Tags"}, []interface{}{2, 1}},
+		{KeepFirst, []string{"Tags"}, []interface{}{1, 2, 1}},
+	}
+
+	for _, test := range tests {
+		deduplicated, err := table.DropDuplicates(test.keep, "", false, false, test.colnames...)
+		if err != nil {
+			t.Fatalf("TestDropDuplicates: could not drop duplicates: %s", err.Error())
+		}
+		checkValues(t, "TestDropDuplicates", deduplicated, "Load", test.loads...)
+	}
+
+	if table.GetRowCount() != 6 {
+		t.Errorf("TestDropDuplicates: the original table should not change")
+	}
+}
+
+func TestDropDuplicatesCount(t *testing.T) {
+
+	table := New("Host", "Load")
+	table.AddRow("").Insert("web1", 1)
+	table.AddRow("").Insert("web2", 2)
+	table.AddRow("").Insert("web1", 3)
+	table.AddFooter().Insert("Total", 6)
+
+	deduplicated, err := table.DropDuplicates(KeepFirst, "Count", false, true, "Host")
+	if err != nil {
+		t.Fatalf("TestDropDuplicatesCount: could not drop duplicates: %s", err.Error())
+	}
+	checkValues(t, "TestDropDuplicatesCount", deduplicated, "Load", 1, 2)
+	checkValues(t, "TestDropDuplicatesCount", deduplicated, "Count", 2, 1)
+
+	// The footer is kept, the original header is not changed
+	if _, err := deduplicated.GetRowByName("footer"); err != nil {
+		t.Errorf("TestDropDuplicatesCount: the footer should be kept")
+	}
+	if header := original(table).headAndFoot["header"]; len(header.Cells) != 2 {
+		t.Errorf("TestDropDuplicatesCount: the original header should not change")
+	}
+
+	// Inplace
+	if _, err := table.DropDuplicates(KeepLast, "Count", true, false, "Host"); err != nil {
+		t.Fatalf("TestDropDuplicatesCount: could not drop duplicates: %s", err.Error())
+	}
+	checkValues(t, "TestDropDuplicatesCount", table, "Load", 2, 3)
+	checkValues(t, "TestDropDuplicatesCount", table, "Count", 1, 2)
+}
+
+func TestDistinct(t *testing.T) {
+
+	table := New("Host", "Tags", "Load")
+	table.AddRow("").Insert("web1", []string{"a", "b"}, 1)
+	table.AddRow("").Insert("web2", []string{"a", "b"}, 1)
+	table.AddRow("").Insert("web3", []string{"a"}, 1)
+
+	if _, err := New().Distinct(); err == nil {
+		t.Errorf("TestDistinct: tables without a header should fail")
+	}
+
+	distinct, err := table.Distinct("Tags", "Load")
+	if err != nil {
+		t.Fatalf("TestDistinct: could not find distinct values: %s", err.Error())
+	}
+	checkValues(t, "TestDistinct", distinct, "Tags", []string{"a", "b"}, []string{"a"})
+	if names := distinct.GetRowNames(); len(names) != 3 {
+		t.Errorf("TestDistinct: expected a header and 2 rows, got %v", names)
+	}
+}
@@ -0,0 +1,157 @@
+package lentele
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// RenderExpanded renders the table vertically, one record per row
+// NB: locks t
+func (t *table) RenderExpanded(dst io.Writer, measureModified, modified, centered bool, template Template, columns ...string) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.renderExpanded(dst, measureModified, modified, centered, template, columns...)
+}
+
+// RenderAuto renders the table regularly or, if it does not fit into width,
+// vertically
+// NB: locks t
+func (t *table) RenderAuto(dst io.Writer, width int, measureModified, modified, centered bool, template Template, columns ...string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if width <= 0 {
+		width = terminalWidth()
+	}
+
+	if width > 0 {
+		_, _, widths, _, headRow, _, _ := t.prepareCells(measureModified, modified, template, columns...)
+		if headRow != -1 {
+			t.headerTiers(widths, columns...)
+		}
+		if tableWidth(widths) > width {
+			t.renderExpanded(dst, measureModified, modified, centered, template, columns...)
+			return
+		}
+	}
+
+	t.render(dst, measureModified, modified, centered, template, columns...)
+}
+
+// renderExpanded writes a vertically rendered table into an io.Writer
+func (t *table) renderExpanded(dst io.Writer, measureModified, modified, centered bool, template Template, columns ...string) {
+
+	// Prepare cells
+	measureRows, printRows, _, _, headRow, footRow, _ := t.prepareCells(measureModified, modified, template, columns...)
+
+	// Keys (column names including their header groups)
+	if headRow != -1 {
+		t.flattenHeader(measureRows[headRow], printRows[headRow], nil, columns...)
+	}
+	cols := 0
+	for _, mrow := range measureRows {
+		if len(mrow) > cols {
+			cols = len(mrow)
+		}
+	}
+
+	mkeys := make([]string, cols, cols)
+	pkeys := make([]string, cols, cols)
+	for j := 0; j < cols; j++ {
+		if headRow != -1 && j < len(printRows[headRow]) {
+			mkeys[j] = strings.TrimSpace(measureRows[headRow][j])
+			pkeys[j] = strings.TrimSpace(printRows[headRow][j])
+		} else {
+			mkeys[j] = fmt.Sprintf("COL_%d", j)
+			pkeys[j] = mkeys[j]
+		}
+	}
+
+	// Measure keys and values
+	keyWidth, valueWidth := 0, 0
+	for _, key := range mkeys {
+		if length := utf8.RuneCountInString(key); length > keyWidth {
+			keyWidth = length
+		}
+	}
+	for i, mrow := range measureRows {
+		if i == headRow {
+			continue
+		}
+		for _, mcell := range mrow {
+			for _, part := range strings.Split(mcell, "\n") {
+				if length := utf8.RuneCountInString(part); length > valueWidth {
+					valueWidth = length
+				}
+			}
+		}
+	}
+
+	// Set template widths
+	template.SetColumnWidths([]int{keyWidth, valueWidth})
+	template.SetDisplayOptions(centered)
+
+	// Prepare table slice
+	lines := []string{""}
+
+	// Title
+	if len(t.Titles) > 0 {
+		lines = append(lines, template.RenderTitles(t.Titles)...)
+	}
+
+	// Render records
+	record := 1
+	for i := range measureRows {
+		if i == headRow || i == footRow || measureRows[i] == nil {
+			continue
+		}
+
+		label := fmt.Sprintf("RECORD %d", record)
+		if name := t.RowNames[i]; name != "" {
+			label = fmt.Sprintf("%s: %s", label, name)
+		}
+
+		lines = append(lines, template.RenderRecord(label, mkeys, pkeys, measureRows[i], printRows[i])...)
+		record++
+	}
+
+	// Render footer
+	if footRow != -1 && len(measureRows[footRow]) > 0 {
+		lines = append(lines, template.RenderRecord("FOOTER", mkeys, pkeys, measureRows[footRow], printRows[footRow])...)
+	}
+
+	// Render Footnotes
+	if len(t.Footnotes) > 0 {
+		lines = append(lines, template.RenderFootnotes(t.Footnotes)...)
+	}
+
+	// Write to destination
+	dst.Write([]byte(strings.Join(lines, "\n")))
+}
+
+// tableWidth returns the width of a rendered table with the given column widths
+func tableWidth(widths []int) int {
+	total := 1
+	for _, width := range widths {
+		if width == 0 {
+			continue
+		}
+		total += width + 3
+	}
+	return total
+}
+
+// terminalWidth returns the width of the terminal or 0 if it is not available
+func terminalWidth() int {
+	w, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return w
+}
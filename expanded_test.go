@@ -0,0 +1,54 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderExpanded(t *testing.T) {
+
+	table := buildGDPTable(true, true, true)
+
+	out := bytes.NewBuffer([]byte{})
+	table.RenderExpanded(out, false, true, false, MustLoadTemplate("classic"), "Year", "Inflation")
+
+	rendered := out.String()
+	if n := strings.Count(rendered, "[ RECORD "); n != 21 {
+		t.Errorf("TestRenderExpanded: expected 21 records, got %d", n)
+	}
+	if !strings.Contains(rendered, "─[ RECORD 21: typo ]") {
+		t.Errorf("TestRenderExpanded: named rows should be labelled by their names")
+	}
+	if !strings.Contains(rendered, "─[ FOOTER ]") {
+		t.Errorf("TestRenderExpanded: the footer should be rendered as the last record")
+	}
+	if !strings.Contains(rendered, "Year      │ 1996") {
+		t.Errorf("TestRenderExpanded: keys should be padded to the longest column name")
+	}
+	if strings.Contains(rendered, "GDP growth │") {
+		t.Errorf("TestRenderExpanded: unselected columns should not be rendered")
+	}
+}
+
+func TestRenderAuto(t *testing.T) {
+
+	tests := []struct {
+		width    int
+		expanded bool
+	}{
+		{200, false},
+		{20, true},
+	}
+
+	for i, test := range tests {
+		table := buildGDPTable(true, true, true)
+
+		out := bytes.NewBuffer([]byte{})
+		table.RenderAuto(out, test.width, false, true, false, MustLoadTemplate("classic"))
+
+		if expanded := strings.Contains(out.String(), "[ RECORD 1 ]"); expanded != test.expanded {
+			t.Errorf("TestRenderAuto: test %d failed: expected expanded=%v", i+1, test.expanded)
+		}
+	}
+}
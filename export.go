@@ -0,0 +1,144 @@
+package lentele
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// MarshalToCSV marshals the header and the body rows as CSV
+// NB: locks t
+func (t *table) MarshalToCSV(dst io.Writer) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	_, printRows, _, _, headRow, footRow, _ := t.prepareCells(false, false, tmplClassic())
+
+	buf := bytes.NewBuffer([]byte{})
+	writer := csv.NewWriter(buf)
+
+	// Header (including header groups)
+	if headRow != -1 {
+		header := make([]string, len(printRows[headRow]))
+		copy(header, printRows[headRow])
+		t.flattenHeader(make([]string, len(header)), header, nil)
+
+		for j := range header {
+			header[j] = strings.TrimSpace(header[j])
+		}
+		writer.Write(header)
+	}
+
+	// Body rows (missing values are empty)
+	for i, printRow := range printRows {
+		if i == headRow || i == footRow || printRow == nil {
+			continue
+		}
+		for j := range printRow {
+			printRow[j] = strings.TrimSpace(printRow[j])
+			if j >= len(t.Rows[i].Cells) || isMissing(t.Rows[i].Cells[j].Value) {
+				printRow[j] = ""
+			}
+		}
+		writer.Write(printRow)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, fmt.Errorf("MarshalToCSV: could not marshal to CSV: %s", err.Error())
+	}
+
+	// Write to destination
+	return dst.Write(buf.Bytes())
+}
+
+// MarshalToHTML marshals the table as an HTML table
+// NB: locks t
+func (t *table) MarshalToHTML(dst io.Writer) (int, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	_, printRows, widths, spans, headRow, footRow, _ := t.prepareCells(false, false, tmplClassic())
+
+	// Order of body rows (for row spans)
+	body := []int{}
+	for i := range printRows {
+		if i != headRow && i != footRow && printRows[i] != nil {
+			body = append(body, i)
+		}
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	buf.WriteString("<table>\n")
+
+	// Titles
+	if len(t.Titles) > 0 {
+		titles := make([]string, len(t.Titles))
+		for i, title := range t.Titles {
+			titles[i] = html.EscapeString(title)
+		}
+		fmt.Fprintf(buf, "  <caption>%s</caption>\n", strings.Join(titles, "<br>"))
+	}
+
+	// Header groups and header
+	if headRow != -1 {
+		buf.WriteString("  <thead>\n")
+		for _, tier := range t.headerTiers(widths) {
+			writeHTMLRow(buf, "th", tier.pcells, tier.spans, nil)
+		}
+		writeHTMLRow(buf, "th", printRows[headRow], spans[headRow], nil)
+		buf.WriteString("  </thead>\n")
+	}
+
+	// Body rows
+	buf.WriteString("  <tbody>\n")
+	for n, i := range body {
+		rowSpan := func(j int) int {
+			count := 1
+			for k := n + 1; k < len(body) && spans[body[k]].up(j); k++ {
+				count++
+			}
+			return count
+		}
+		writeHTMLRow(buf, "td", printRows[i], spans[i], rowSpan)
+	}
+	buf.WriteString("  </tbody>\n")
+
+	// Footer
+	if footRow != -1 {
+		buf.WriteString("  <tfoot>\n")
+		writeHTMLRow(buf, "td", printRows[footRow], spans[footRow], nil)
+		buf.WriteString("  </tfoot>\n")
+	}
+
+	buf.WriteString("</table>\n")
+
+	// Write to destination
+	return dst.Write(buf.Bytes())
+}
+
+// writeHTMLRow writes a table row skipping merged cells
+func writeHTMLRow(buf *bytes.Buffer, tag string, cells []string, spans Spans, rowSpan func(j int) int) {
+
+	buf.WriteString("    <tr>")
+	for j, cell := range cells {
+		if spans.cols(j) == 0 || spans.up(j) {
+			continue
+		}
+
+		attrs := ""
+		if cols := spans.cols(j); cols > 1 {
+			attrs += fmt.Sprintf(" colspan=\"%d\"", cols)
+		}
+		if spans.down(j) && rowSpan != nil {
+			attrs += fmt.Sprintf(" rowspan=\"%d\"", rowSpan(j))
+		}
+
+		value := strings.Replace(html.EscapeString(strings.TrimSpace(cell)), "\n", "<br>", -1)
+		fmt.Fprintf(buf, "<%s%s>%s</%s>", tag, attrs, value, tag)
+	}
+	buf.WriteString("</tr>\n")
+}
@@ -0,0 +1,59 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMarshalToCSV(t *testing.T) {
+
+	table := buildGroupedTable()
+	table.AddFooter().Insert("Total", 5, 7, 9)
+	table.AddRow("").Insert("West, \"new\"", 0, 0, 0)
+	table.SetFormat("%6v", "Units")
+
+	out := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToCSV(out); err != nil {
+		t.Fatalf("TestMarshalToCSV: could not marshal table: %s", err.Error())
+	}
+
+	expected := strings.Join([]string{
+		"Region,Sales / Revenue / EUR,Sales / Revenue / USD,Sales / Units",
+		"North,1,2,3",
+		"South,4,5,6",
+		`"West, ""new""",0,0,0`,
+		"",
+	}, "\n")
+
+	if out.String() != expected {
+		t.Errorf("TestMarshalToCSV: expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestMarshalToHTML(t *testing.T) {
+
+	table := buildSpanTable()
+	table.AddTitle("Sales <2017>")
+	table.AddHeaderGroup("Q1", 1, "Jan", "Mar")
+
+	out := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToHTML(out); err != nil {
+		t.Fatalf("TestMarshalToHTML: could not marshal table: %s", err.Error())
+	}
+
+	for _, line := range []string{
+		"<caption>Sales &lt;2017&gt;</caption>",
+		`<tr><th></th><th colspan="3">Q1</th></tr>`,
+		"<tr><th>Region</th><th>Jan</th><th>Feb</th><th>Mar</th></tr>",
+		`<tr><td colspan="4">Quarterly sales</td></tr>`,
+		`<tr><td rowspan="2">North</td><td>1</td><td>2</td><td>3</td></tr>`,
+		"<tr><td>4</td><td>5</td><td>6</td></tr>",
+		`<tr><td>South</td><td colspan="3">Closed for renovation</td></tr>`,
+		"<tfoot>\n    <tr><td>Total</td><td>5</td><td>7</td><td>9</td></tr>\n  </tfoot>",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("TestMarshalToHTML: '%s' is missing:\n%s", line, out.String())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package lentele
+
+import (
+	"fmt"
+	"sort"
+)
+
+// frequency is the count of a distinct value
+type frequency struct {
+	value interface{} // First raw value
+	count int
+}
+
+// ValueCounts returns a frequency table of the values of the column's body
+// rows (most frequent first), with the count, the percentage and the
+// cumulative percentage of every value. If bar is set, the counts are also
+// visualized as bars (modified view only). Missing and nil values are not
+// counted.
+// NB: locks t
+func (t *table) ValueCounts(column string, bar bool) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	colIdx := t.getColIdx(column)
+	if len(colIdx) == 0 {
+		return nil, fmt.Errorf("ValueCounts: no such column '%s'", column)
+	}
+	col := colIdx[0]
+
+	// Count values in the order of their first appearance
+	frequencies := []*frequency{}
+	index := map[string]*frequency{}
+	total := 0
+	for _, row := range t.bodyRows() {
+		value := cellValue(row, col)
+		if value == nil || isMissing(value) {
+			continue
+		}
+
+		key := fmt.Sprintf("%v", value)
+		if _, ok := index[key]; !ok {
+			index[key] = &frequency{value: value}
+			frequencies = append(frequencies, index[key])
+		}
+		index[key].count++
+		total++
+	}
+
+	sort.SliceStable(frequencies, func(a, b int) bool {
+		return frequencies[a].count > frequencies[b].count
+	})
+
+	// Frequency table
+	columns := []string{fmt.Sprintf("%v", cellValue(t.headAndFoot["header"], col)), "Count", "Percent", "Cumulative"}
+	if bar {
+		columns = append(columns, "Histogram")
+	}
+
+	counts := New(columns...)
+	cumulative := 0
+	for _, f := range frequencies {
+		cumulative += f.count
+		values := []interface{}{f.value, f.count, float64(f.count) / float64(total), float64(cumulative) / float64(total)}
+		if bar {
+			values = append(values, f.count)
+		}
+		counts.AddRow("").Insert(values...)
+	}
+
+	percent := MustLoadLocale("en-US").Percent(1)
+	counts.SetFormatter(percent, "Percent", "Cumulative")
+	if bar {
+		counts.SetCellRenderer(BarRenderer(20), "Histogram")
+	}
+
+	return counts, nil
+}
+
+// Crosstab returns a contingency table of two columns: the distinct values of
+// rowCol are the rows and the distinct values of colCol the columns (in the
+// order of their first appearance). Every cell aggregates the values of
+// valueCol (or, if valueCol is "", of rowCol) of the matching body rows, i.e.
+// Count results in frequencies. The aggregates of whole rows are added as a
+// "Total" column and the aggregates of whole columns as the footer. The
+// aggregate defaults to Count.
+// NB: locks t
+func (t *table) Crosstab(rowCol, colCol, valueCol string, aggregate Aggregate) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if aggregate == nil {
+		aggregate = Count
+	}
+
+	if valueCol == "" {
+		valueCol = rowCol
+	}
+
+	indices := []int{}
+	for _, colname := range []string{rowCol, colCol, valueCol} {
+		colIdx := t.getColIdx(colname)
+		if len(colIdx) == 0 {
+			return nil, fmt.Errorf("Crosstab: no such column '%s'", colname)
+		}
+		indices = append(indices, colIdx[0])
+	}
+
+	// Distinct values
+	rowKeys, colKeys := []string{}, []string{}
+	rowValues := map[string]interface{}{}
+	seenCols := map[string]bool{}
+	cells := map[[2]string][]interface{}{}
+	byRow := map[string][]interface{}{}
+	byCol := map[string][]interface{}{}
+	all := []interface{}{}
+	for _, row := range t.bodyRows() {
+		rowValue := cellValue(row, indices[0])
+		rowKey := fmt.Sprintf("%v", rowValue)
+		colKey := fmt.Sprintf("%v", cellValue(row, indices[1]))
+		value := cellValue(row, indices[2])
+
+		if _, ok := rowValues[rowKey]; !ok {
+			rowValues[rowKey] = rowValue
+			rowKeys = append(rowKeys, rowKey)
+		}
+		if !seenCols[colKey] {
+			seenCols[colKey] = true
+			colKeys = append(colKeys, colKey)
+		}
+
+		cells[[2]string{rowKey, colKey}] = append(cells[[2]string{rowKey, colKey}], value)
+		byRow[rowKey] = append(byRow[rowKey], value)
+		byCol[colKey] = append(byCol[colKey], value)
+		all = append(all, value)
+	}
+
+	// aggregated treats nil aggregates as missing
+	aggregated := func(values []interface{}) interface{} {
+		if result := aggregate(values); result != nil {
+			return result
+		}
+		return Missing
+	}
+
+	// Contingency table
+	header := t.headAndFoot["header"]
+	columns := append([]string{fmt.Sprintf("%v", cellValue(header, indices[0]))}, colKeys...)
+	crosstab := New(append(columns, "Total")...)
+
+	for _, rowKey := range rowKeys {
+		values := []interface{}{rowValues[rowKey]}
+		for _, colKey := range colKeys {
+			values = append(values, aggregated(cells[[2]string{rowKey, colKey}]))
+		}
+		crosstab.AddRow("").Insert(append(values, aggregated(byRow[rowKey]))...)
+	}
+
+	// Margins
+	totals := []interface{}{"Total"}
+	for _, colKey := range colKeys {
+		totals = append(totals, aggregated(byCol[colKey]))
+	}
+	crosstab.AddFooter().Insert(append(totals, aggregated(all))...)
+
+	return crosstab, nil
+}
@@ -0,0 +1,71 @@
+package lentele
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValueCounts(t *testing.T) {
+
+	table := New("Service")
+	for _, service := range []interface{}{"api", "db", "api", Missing, nil, "auth", "db", "api"} {
+		table.AddRow("").Insert(service)
+	}
+
+	if _, err := table.ValueCounts("Host", false); err == nil {
+		t.Errorf("TestValueCounts: unknown columns should fail")
+	}
+
+	// Most frequent values first (ties in the order of appearance), missing
+	// and nil values are not counted
+	counts, err := table.ValueCounts("Service", false)
+	if err != nil {
+		t.Fatalf("TestValueCounts: could not count values: %s", err.Error())
+	}
+	checkValues(t, "TestValueCounts", counts, "Service", "api", "db", "auth")
+	checkValues(t, "TestValueCounts", counts, "Count", 3, 2, 1)
+	checkValues(t, "TestValueCounts", counts, "Cumulative", 0.5, 5.0/6, 1.0)
+	if columnValues(counts, "Histogram") != nil {
+		t.Errorf("TestValueCounts: bars should be optional")
+	}
+
+	// Percentages are formatted, bars only rendered in the modified view
+	counts, _ = table.ValueCounts("Service", true)
+	if rendered := renderMySQL(counts); !strings.Contains(rendered, "|   db    |   2   |  33.3%  |   83.3%    |    █████████████▎    |") {
+		t.Errorf("TestValueCounts: unexpected output:\n%s", rendered)
+	}
+}
+
+func TestCrosstab(t *testing.T) {
+
+	table := New("Service", "Level", "Bytes")
+	table.AddRow("").Insert("api", "info", 100)
+	table.AddRow("").Insert("api", "error", 250)
+	table.AddRow("").Insert("db", "info", 50)
+	table.AddRow("").Insert("api", "info", Missing)
+
+	if _, err := table.Crosstab("Service", "Host", "", nil); err == nil {
+		t.Errorf("TestCrosstab: unknown columns should fail")
+	}
+
+	// Counts of rows, columns in the order of appearance
+	crosstab, err := table.Crosstab("Service", "Level", "", nil)
+	if err != nil {
+		t.Fatalf("TestCrosstab: could not cross-tabulate: %s", err.Error())
+	}
+	checkValues(t, "TestCrosstab", crosstab, "Service", "api", "db")
+	checkValues(t, "TestCrosstab", crosstab, "info", 2, 1)
+	checkValues(t, "TestCrosstab", crosstab, "error", 1, 0)
+	checkValues(t, "TestCrosstab", crosstab, "Total", 3, 1)
+
+	// Aggregated values with margins in the footer
+	crosstab, _ = table.Crosstab("Service", "Level", "Bytes", Sum)
+	checkValues(t, "TestCrosstab", crosstab, "info", 100.0, 50.0)
+	footer, err := crosstab.GetRowByName("footer")
+	if err != nil {
+		t.Fatalf("TestCrosstab: the margins should be the footer")
+	}
+	if total := cellValue(footer.(*row), 3); total != 400.0 {
+		t.Errorf("TestCrosstab: expected a grand total of 400, got %v", total)
+	}
+}
@@ -0,0 +1,236 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Aggregate reduces the raw values of a column to a single value
+type Aggregate func(values []interface{}) interface{}
+
+// Sum returns the sum of the numeric values
+func Sum(values []interface{}) interface{} {
+	sum := 0.0
+	for _, v := range values {
+		if f, ok := toFloat(v); ok {
+			sum += f
+		}
+	}
+	return sum
+}
+
+// Mean returns the mean of the numeric values (nil if there are none)
+func Mean(values []interface{}) interface{} {
+	sum, count := 0.0, 0
+	for _, v := range values {
+		if f, ok := toFloat(v); ok {
+			sum += f
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	return sum / float64(count)
+}
+
+// Count returns the count of values that are neither nil nor missing
+func Count(values []interface{}) interface{} {
+	count := 0
+	for _, v := range values {
+		if v != nil && !isMissing(v) {
+			count++
+		}
+	}
+	return count
+}
+
+// Min returns the smallest numeric value (nil if there are none)
+func Min(values []interface{}) interface{} {
+	return extremum(values, math.Min)
+}
+
+// Max returns the largest numeric value (nil if there are none)
+func Max(values []interface{}) interface{} {
+	return extremum(values, math.Max)
+}
+
+// extremum reduces the numeric values with pick
+func extremum(values []interface{}, pick func(a, b float64) float64) interface{} {
+	var result interface{}
+	for _, v := range values {
+		if f, ok := toFloat(v); ok {
+			if result == nil {
+				result = f
+			} else {
+				result = pick(result.(float64), f)
+			}
+		}
+	}
+	return result
+}
+
+// grouping describes how body rows are grouped at render time
+type grouping struct {
+	column     string
+	aggregates map[string]Aggregate // Aggregates by column name
+}
+
+// groupBlock is a group of body rows
+type groupBlock struct {
+	title          string
+	rows           []int    // Indices of the rows (t.Rows)
+	mcells, pcells []string // Subtotal, nil if there are no aggregates
+}
+
+// SetGrouping groups the body rows by the values of a column at render time.
+// Every group is rendered with a group title and, if aggregates (by column
+// name) are provided, a subtotal row. If the table has no footer, the
+// aggregates of all the body rows are rendered as the footer.
+// NB: locks t
+func (t *table) SetGrouping(column string, aggregates map[string]Aggregate) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(t.getColIdx(column)) == 0 {
+		return fmt.Errorf("SetGrouping: no such column '%s'", column)
+	}
+
+	for colname, aggregate := range aggregates {
+		if len(t.getColIdx(colname)) == 0 {
+			return fmt.Errorf("SetGrouping: no such column '%s'", colname)
+		}
+		if aggregate == nil {
+			return fmt.Errorf("SetGrouping: aggregate of column '%s' cannot be nil", colname)
+		}
+	}
+
+	t.grouping = &grouping{column: column, aggregates: aggregates}
+
+	return nil
+}
+
+// ClearGrouping renders the rows without groups
+// NB: locks t
+func (t *table) ClearGrouping() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.grouping = nil
+}
+
+// groupBlocks groups the body rows in the order of their first appearance,
+// calculates the subtotals and the grand total (only if there is no footer)
+// and widens the columns so that they fit
+func (t *table) groupBlocks(widths []int, headRow, footRow int, columns ...string) ([]groupBlock, []string) {
+
+	if t.grouping == nil {
+		return nil, nil
+	}
+
+	colIdx := t.getColIdx(t.grouping.column)
+	if len(colIdx) == 0 {
+		return nil, nil
+	}
+	col := colIdx[0]
+	name := t.grouping.column
+	if header := t.headAndFoot["header"]; header != nil && col < len(header.Cells) {
+		name = fmt.Sprintf("%v", header.Cells[col].Value)
+	}
+
+	// Rendered columns
+	positions := t.getColIdx(columns...)
+	if len(positions) == 0 {
+		for k := range widths {
+			positions = append(positions, k)
+		}
+	}
+
+	// Aggregated columns
+	aggregates := map[int]Aggregate{}
+	for colname, aggregate := range t.grouping.aggregates {
+		if idx := t.getColIdx(colname); len(idx) > 0 {
+			aggregates[idx[0]] = aggregate
+		}
+	}
+
+	// Group rows
+	blocks := []groupBlock{}
+	index := map[string]int{}
+	body := []int{}
+	for i, row := range t.Rows {
+		if i == headRow || i == footRow {
+			continue
+		}
+		body = append(body, i)
+
+		var value interface{}
+		if col < len(row.Cells) {
+			value = row.Cells[col].Value
+		}
+		key := fmt.Sprintf("%v", value)
+
+		if _, ok := index[key]; !ok {
+			index[key] = len(blocks)
+			blocks = append(blocks, groupBlock{title: fmt.Sprintf("%s: %s", name, key)})
+		}
+		blocks[index[key]].rows = append(blocks[index[key]].rows, i)
+	}
+
+	// Subtotals and titles
+	var total []string
+	for b := range blocks {
+		if len(aggregates) > 0 {
+			blocks[b].mcells = t.aggregateRow(blocks[b].rows, "Subtotal", positions, aggregates, widths)
+			blocks[b].pcells = blocks[b].mcells
+		}
+		distributeSpans(widths, []spannedCell{{pos: 0, span: len(widths), length: utf8.RuneCountInString(blocks[b].title)}})
+	}
+	if len(aggregates) > 0 && footRow == -1 {
+		total = t.aggregateRow(body, "Total", positions, aggregates, widths)
+	}
+
+	return blocks, total
+}
+
+// aggregateRow calculates the aggregates of the rows. The first column
+// contains the label, unless it is aggregated.
+func (t *table) aggregateRow(rows []int, label string, positions []int, aggregates map[int]Aggregate, widths []int) []string {
+
+	cells := make([]string, len(positions))
+	for j, col := range positions {
+
+		aggregate, ok := aggregates[col]
+		if !ok {
+			if j == 0 {
+				cells[j] = label
+			}
+		} else {
+			values := []interface{}{}
+			for _, i := range rows {
+				if col < len(t.Rows[i].Cells) {
+					values = append(values, t.Rows[i].Cells[col].Value)
+				}
+			}
+
+			format, ok := t.Formats[j]
+			if !ok {
+				format = "%v"
+			}
+			result := aggregate(values)
+			if formatted, _, ok := t.formatValue(col, j, result); ok {
+				cells[j] = formatted
+			} else if cells[j] = fmt.Sprintf(format, result); strings.Contains(cells[j], "%!") {
+				cells[j] = fmt.Sprintf("%v", result)
+			}
+		}
+
+		if length := utf8.RuneCountInString(cells[j]); j < len(widths) && length > widths[j] {
+			widths[j] = length
+		}
+	}
+
+	return cells
+}
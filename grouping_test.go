@@ -0,0 +1,109 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// buildShopTable builds a small table of shops by region
+func buildShopTable() Table {
+	table := New("Region", "Shop", "Sales")
+	table.AddRow("").Insert("North", "A", 10)
+	table.AddRow("").Insert("South", "B", 5)
+	table.AddRow("").Insert("North", "C", 7)
+	table.AddRow("").Insert("South", "D", 3)
+
+	return table
+}
+
+func TestAggregates(t *testing.T) {
+
+	values := []interface{}{3, 1.5, "n/a", nil, uint8(4)}
+
+	tests := []struct {
+		aggregate Aggregate
+		expected  interface{}
+	}{
+		{Sum, 8.5},
+		{Mean, 8.5 / 3},
+		{Count, 4},
+		{Min, 1.5},
+		{Max, 4.0},
+	}
+
+	for i, test := range tests {
+		if result := test.aggregate(values); result != test.expected {
+			t.Errorf("TestAggregates: test %d failed: expected %v, got %v", i+1, test.expected, result)
+		}
+	}
+
+	if Mean([]interface{}{"a"}) != nil || Max(nil) != nil {
+		t.Errorf("TestAggregates: aggregates of non-numeric values should be nil")
+	}
+}
+
+func TestGrouping(t *testing.T) {
+
+	table := buildShopTable()
+
+	if err := table.SetGrouping("Country", nil); err == nil {
+		t.Errorf("TestGrouping: unknown columns should fail")
+	}
+	if err := table.SetGrouping("Region", map[string]Aggregate{"Profit": Sum}); err == nil {
+		t.Errorf("TestGrouping: unknown aggregated columns should fail")
+	}
+	if err := table.SetGrouping("Region", map[string]Aggregate{"Sales": Sum}); err != nil {
+		t.Fatalf("TestGrouping: could not set grouping: %s", err.Error())
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+
+	expected := []string{
+		"+----------+------+-------+",
+		"|  Region  | Shop | Sales |",
+		"+----------+------+-------+",
+		"| Region: North           |",
+		"|  North   |  A   |  10   |",
+		"|  North   |  C   |   7   |",
+		"+----------+------+-------+",
+		"| Subtotal |      |  17   |",
+		"+==========+======+=======+",
+		"| Region: South           |",
+		"|  South   |  B   |   5   |",
+		"|  South   |  D   |   3   |",
+		"+----------+------+-------+",
+		"| Subtotal |      |   8   |",
+		"+----------+------+-------+",
+		"|  Total   |      |  25   |",
+		"+----------+------+-------+",
+	}
+
+	if rendered := out.String(); !strings.Contains(rendered, strings.Join(expected, "\n")) {
+		t.Errorf("TestGrouping: unexpected output:\n%s", rendered)
+	}
+
+	// Grouping survives filtering
+	filtered, _ := table.Filter(func(values ...interface{}) bool { return values[0] != "A" }, false, false, "Shop")
+	out.Reset()
+	filtered.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if !strings.Contains(out.String(), "| Subtotal |      |   7   |") || !strings.Contains(out.String(), "|  Total   |      |  15   |") {
+		t.Errorf("TestGrouping: filtered tables should be grouped:\n%s", out.String())
+	}
+
+	// Existing footers are kept
+	table.AddFooter().Insert("All", "", "-")
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if strings.Contains(out.String(), "Total") || !strings.Contains(out.String(), "|   All    |      |   -   |") {
+		t.Errorf("TestGrouping: footer should not be replaced:\n%s", out.String())
+	}
+
+	table.ClearGrouping()
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if strings.Contains(out.String(), "Subtotal") {
+		t.Errorf("TestGrouping: grouping should be removed")
+	}
+}
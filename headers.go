@@ -0,0 +1,222 @@
+package lentele
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// HeaderGroup is a label spanning a range of columns above the header.
+// Level 1 groups are rendered right above the header, level 2 groups above
+// the level 1 groups and so on.
+type HeaderGroup struct {
+	Label string `json:"label"`
+	Level int    `json:"level"`
+	First int    `json:"first"` // Index of the first column
+	Last  int    `json:"last"`  // Index of the last column
+}
+
+// headerTier is a rendered row of header groups
+type headerTier struct {
+	mcells, pcells []string
+	spans          Spans
+}
+
+// AddHeaderGroup adds a label spanning the columns from first to last (both
+// included) above the header. Groups of the same level cannot overlap.
+// NB: locks t
+func (t *table) AddHeaderGroup(label string, level int, first, last string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if level < 1 {
+		return fmt.Errorf("AddHeaderGroup: level must be positive")
+	}
+
+	if _, ok := t.headAndFoot["header"]; !ok {
+		return fmt.Errorf("AddHeaderGroup: table has no header")
+	}
+
+	firstIdx := t.getColIdx(first)
+	if len(firstIdx) == 0 {
+		return fmt.Errorf("AddHeaderGroup: no such column '%s'", first)
+	}
+	lastIdx := t.getColIdx(last)
+	if len(lastIdx) == 0 {
+		return fmt.Errorf("AddHeaderGroup: no such column '%s'", last)
+	}
+
+	group := &HeaderGroup{Label: label, Level: level, First: firstIdx[0], Last: lastIdx[0]}
+	if group.First > group.Last {
+		return fmt.Errorf("AddHeaderGroup: column '%s' comes after column '%s'", first, last)
+	}
+
+	for _, other := range t.HeaderGroups {
+		if other.Level == level && other.First <= group.Last && group.First <= other.Last {
+			return fmt.Errorf("AddHeaderGroup: group '%s' overlaps with group '%s'", label, other.Label)
+		}
+	}
+
+	t.HeaderGroups = append(t.HeaderGroups, group)
+
+	return nil
+}
+
+// ClearHeaderGroups removes all the header groups
+// NB: locks t
+func (t *table) ClearHeaderGroups() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.HeaderGroups = nil
+}
+
+// headerGroup returns the group of the column at the given level
+func (t *table) headerGroup(level, col int) *HeaderGroup {
+	for _, group := range t.HeaderGroups {
+		if group.Level == level && col >= group.First && col <= group.Last {
+			return group
+		}
+	}
+	return nil
+}
+
+// headerColumns returns the indices of the rendered header columns by their
+// position
+func (t *table) headerColumns(columns ...string) []int {
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil
+	}
+
+	colIdx := t.getColIdx(columns...)
+	if len(colIdx) == 0 {
+		for k := range header.Cells {
+			colIdx = append(colIdx, k)
+		}
+	}
+
+	positions := []int{}
+	for _, col := range colIdx {
+		if col < len(header.Cells) {
+			positions = append(positions, col)
+		}
+	}
+
+	return positions
+}
+
+// headerTiers prepares the header groups of the rendered columns (topmost
+// tier first) and widens the columns, so that the group labels fit
+func (t *table) headerTiers(widths []int, columns ...string) []headerTier {
+
+	if len(t.HeaderGroups) == 0 {
+		return nil
+	}
+
+	levels := 0
+	for _, group := range t.HeaderGroups {
+		if group.Level > levels {
+			levels = group.Level
+		}
+	}
+
+	positions := t.headerColumns(columns...)
+	if len(positions) > len(widths) {
+		positions = positions[:len(widths)]
+	}
+
+	tiers := []headerTier{}
+	wide := []spannedCell{}
+	for level := levels; level >= 1; level-- {
+
+		tier := headerTier{}
+		start := -1
+		var previous *HeaderGroup
+		for p, col := range positions {
+
+			// Consecutive columns of a group are merged
+			group := t.headerGroup(level, col)
+			if group != nil && group == previous {
+				tier.spans.Cols[start]++
+				tier.spans.add(0, false, false)
+				tier.mcells = append(tier.mcells, "")
+				tier.pcells = append(tier.pcells, "")
+				continue
+			}
+
+			label := ""
+			if group != nil {
+				label = group.Label
+			}
+
+			previous, start = group, p
+			tier.spans.add(1, false, false)
+			tier.mcells = append(tier.mcells, label)
+			tier.pcells = append(tier.pcells, label)
+		}
+
+		// Fit labels
+		for p, cols := range tier.spans.Cols {
+			length := utf8.RuneCountInString(tier.mcells[p])
+			if cols > 1 {
+				wide = append(wide, spannedCell{pos: p, span: cols, length: length})
+			} else if cols == 1 && length > widths[p] {
+				widths[p] = length
+			}
+		}
+
+		// Tiers are joined with the tier above and the header below
+		tier.spans.Below = true
+		if len(tiers) > 0 {
+			tier.spans.Above = tiers[len(tiers)-1].spans.Cols
+		}
+
+		tiers = append(tiers, tier)
+	}
+
+	distributeSpans(widths, wide)
+
+	return tiers
+}
+
+// flattenHeader prefixes the header cells with the labels of their groups,
+// e.g. "Revenue / EUR", and widens the columns accordingly
+func (t *table) flattenHeader(mcells, pcells []string, widths []int, columns ...string) {
+
+	if len(t.HeaderGroups) == 0 {
+		return
+	}
+
+	levels := 0
+	for _, group := range t.HeaderGroups {
+		if group.Level > levels {
+			levels = group.Level
+		}
+	}
+
+	for p, col := range t.headerColumns(columns...) {
+		if p >= len(mcells) {
+			break
+		}
+
+		labels := []string{}
+		for level := levels; level >= 1; level-- {
+			if group := t.headerGroup(level, col); group != nil {
+				labels = append(labels, group.Label)
+			}
+		}
+		if len(labels) == 0 {
+			continue
+		}
+
+		prefix := strings.Join(labels, " / ") + " / "
+		mcells[p] = prefix + strings.TrimSpace(mcells[p])
+		pcells[p] = prefix + strings.TrimSpace(pcells[p])
+
+		if length := utf8.RuneCountInString(mcells[p]); p < len(widths) && length > widths[p] {
+			widths[p] = length
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// buildGroupedTable builds a small table with two header tiers
+func buildGroupedTable() Table {
+	table := New("Region", "EUR", "USD", "Units")
+	table.AddRow("").Insert("North", 1, 2, 3)
+	table.AddRow("").Insert("South", 4, 5, 6)
+	table.AddHeaderGroup("Revenue", 1, "EUR", "USD")
+	table.AddHeaderGroup("Sales", 2, "EUR", "Units")
+
+	return table
+}
+
+func TestHeaderGroups(t *testing.T) {
+
+	table := buildGroupedTable()
+
+	// Invalid groups
+	if err := table.AddHeaderGroup("Bad", 0, "EUR", "USD"); err == nil {
+		t.Errorf("TestHeaderGroups: levels must be positive")
+	}
+	if err := table.AddHeaderGroup("Bad", 1, "EUR", "GBP"); err == nil {
+		t.Errorf("TestHeaderGroups: unknown columns should fail")
+	}
+	if err := table.AddHeaderGroup("Bad", 1, "USD", "EUR"); err == nil {
+		t.Errorf("TestHeaderGroups: reversed ranges should fail")
+	}
+	if err := table.AddHeaderGroup("Bad", 1, "USD", "Units"); err == nil {
+		t.Errorf("TestHeaderGroups: overlapping groups should fail")
+	}
+	if err := New().AddHeaderGroup("Bad", 1, "EUR", "USD"); err == nil {
+		t.Errorf("TestHeaderGroups: tables without a header should fail")
+	}
+
+	// Tiers
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("classic"))
+
+	expected := []string{
+		"╔════════╦═══════════════════╗",
+		"║        ║       Sales       ║",
+		"╠════════╬═══════════╦═══════╣",
+		"║        ║  Revenue  ║       ║",
+		"╠════════╬═════╦═════╬═══════╣",
+		"║ Region ║ EUR ║ USD ║ Units ║",
+		"╠════════╩═════╩═════╩═══════╣",
+	}
+	if rendered := out.String(); !strings.Contains(rendered, strings.Join(expected, "\n")) {
+		t.Errorf("TestHeaderGroups: unexpected output:\n%s", rendered)
+	}
+
+	// Flattened headers
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("markdown"))
+	if !strings.Contains(out.String(), "| Region | Sales / Revenue / EUR | Sales / Revenue / USD | Sales / Units |") {
+		t.Errorf("TestHeaderGroups: markdown headers should be flattened:\n%s", out.String())
+	}
+
+	// Hidden columns
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"), "Region", "USD")
+	if !strings.Contains(out.String(), "|        |  Sales  |\n+--------+---------+\n|        | Revenue |\n+--------+---------+\n| Region |   USD   |") {
+		t.Errorf("TestHeaderGroups: groups should only span rendered columns:\n%s", out.String())
+	}
+
+	// Round trip
+	jsoned := bytes.NewBuffer([]byte{})
+	table.MarshalToRichJSON(jsoned)
+	restored, err := NewFromRichJSON(jsoned)
+	if err != nil {
+		t.Fatalf("TestHeaderGroups: could not unmarshal table: %s", err.Error())
+	}
+
+	expectedOut, restoredOut := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	table.Render(expectedOut, false, true, false, MustLoadTemplate("classic"))
+	restored.Render(restoredOut, false, true, false, MustLoadTemplate("classic"))
+	if expectedOut.String() != restoredOut.String() {
+		t.Errorf("TestHeaderGroups: header groups should survive a round trip")
+	}
+
+	// Removal
+	table.ClearHeaderGroups()
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("classic"))
+	if strings.Contains(out.String(), "Sales") {
+		t.Errorf("TestHeaderGroups: header groups should be removed")
+	}
+}
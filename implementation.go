@@ -22,6 +22,12 @@ func New(columns ...string) Table {
 		Formats:        map[int]string{},
 		Footnotes:      []string{},
 		WidthOverrides: map[int]int{},
+		renderers:      map[int]CellRenderer{},
+		formatters:     map[int]Formatter{},
+		placeholders:   map[int]string{},
+		keyIndexes:     map[string]*keyIndex{},
+		indexed:        map[int]bool{},
+		revision:       new(uint32),
 		headAndFoot:    map[string]*row{},
 	}
 
@@ -38,6 +44,9 @@ func New(columns ...string) Table {
 // of objects, e.g.:
 //
 // bytes.NewBuffer([]byte(`[{col1: "val1", col2: "val2"},{col1: "val3", col2: "val4"}]`))
+//
+// Absent keys become missing values (see Missing), which are rendered with
+// missingValue as the placeholder (unless it is nil).
 func NewFromVanillaJSON(source io.Reader, missingValue interface{}) (Table, error) {
 
 	// Read marshaled input
@@ -71,11 +80,16 @@ func NewFromVanillaJSON(source io.Reader, missingValue interface{}) (Table, erro
 			if value, ok := line[colname]; ok {
 				row.Insert(value)
 			} else {
-				row.Insert(missingValue)
+				row.Insert(Missing)
 			}
 		}
 	}
 
+	// Missing values are only rendered with the placeholder
+	if missingValue != nil {
+		newTable.SetPlaceholder(fmt.Sprintf("%v", missingValue))
+	}
+
 	return newTable, nil
 }
 
@@ -97,12 +111,24 @@ func NewFromRichJSON(source io.Reader) (Table, error) {
 		Formats:        map[int]string{},
 		Footnotes:      []string{},
 		WidthOverrides: map[int]int{},
+		renderers:      map[int]CellRenderer{},
+		formatters:     map[int]Formatter{},
+		placeholders:   map[int]string{},
+		keyIndexes:     map[string]*keyIndex{},
+		indexed:        map[int]bool{},
+		revision:       new(uint32),
 		headAndFoot:    map[string]*row{},
 	}
 	if err := json.Unmarshal(jsoned, tableProtype); err != nil {
 		return nil, fmt.Errorf("NewFromVanillaJSON: could not unmarshal data: %s", err.Error())
 	}
 
+	// Restore style rules, the tree structure and nested tables
+	tableProtype.Rules = restoreRules(tableProtype.Rules)
+	tableProtype.restoreParents()
+	tableProtype.restoreNested()
+	tableProtype.restoreMissing()
+
 	// Add mutexes
 	for i, row := range tableProtype.Rows {
 		// Add mutexes
@@ -133,18 +159,6 @@ func readMarshaled(source io.Reader) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// LoadTemplate returns the named template
-func LoadTemplate(name string) Template {
-	switch strings.ToLower(name) {
-	case "smooth":
-		return tmplSmooth()
-	case "modern":
-		return tmplModern()
-	default:
-		return tmplClassic()
-	}
-}
-
 // table implements the lentele.Table interface
 type table struct {
 	*sync.Mutex    `json:",omit"`
@@ -154,15 +168,35 @@ type table struct {
 	Titles         []string       `json:"titles"`
 	Footnotes      []string       `json:"footnotes"`
 	WidthOverrides map[int]int    `json:"width"`
-
-	headAndFoot map[string]*row // Map of addresses to header and footer pointers
+	Strict         bool           `json:"strict,omitempty"`
+	Rules          []*StyleRule   `json:"rules,omitempty"`
+	HeaderGroups   []*HeaderGroup `json:"headerGroups,omitempty"`
+
+	headAndFoot  map[string]*row      // Map of addresses to header and footer pointers
+	renderers    map[int]CellRenderer // Cell visualizations by column index
+	formatters   map[int]Formatter    // Value formatters by column index
+	typeFormats  []*TypeFormat        // Type formats, the latest first
+	placeholders map[int]string       // Placeholders of missing values by column index (-1 = all columns)
+	grouping     *grouping            // Row groups and subtotals
+	tree         treeOptions          // Rendering of tree tables
+	nested       Template             // Template of nested tables
+	enclosing    map[*table]bool      // Tables rendering t nested inside their cells
+	keyIndexes   map[string]*keyIndex // Indexes of key columns
+	indexed      map[int]bool         // Columns indexed for FindRows
+	rowIndex     map[string][]*row    // Rows by name (nil = not built)
+	colIndex     *columnIndex         // Column positions by name
+	revision     *uint32              // Count of changes of the (shared) rows (atomic)
 }
 
 // row implements the lentele.Row interface
 type row struct {
 	*sync.Mutex `json:",omit"`
 	Cells       []*cell `json:"cells"`
+	Parent      *int    `json:"parent,omitempty"` // Index of the parent row (only set when marshaling)
 	tref        *table  // Parent table reference
+	parent      *row    // Parent row of tree tables
+	revision    uint32  // Count of value changes (atomic)
+	err         error   // First error recorded in strict mode
 }
 
 // value stores individual cell values
@@ -170,6 +204,9 @@ type cell struct {
 	*sync.Mutex `json:",omit"`
 	Value       interface{} `json:"value"`
 	ModVal      interface{} `json:"modified"`
+	ColSpan     int         `json:"colspan,omitempty"`
+	RowSpan     int         `json:"rowspan,omitempty"`
+	Missing     bool        `json:"missing,omitempty"` // Value is missing (only set when marshaling)
 	modFunc     func(v interface{}) interface{}
 }
 
@@ -210,13 +247,13 @@ func (t *table) AddHeader(colnames []string) Row {
 	for i, v := range colnames {
 		iface[i] = v
 	}
-	return t.AddRow("header").Insert(iface...)
+	return t.addSpecialRow("header").Insert(iface...)
 }
 
 // AddFooter adds a footer to the table
 // NB: indirectly locks t
 func (t *table) AddFooter() Row {
-	return t.AddRow("footer")
+	return t.addSpecialRow("footer")
 }
 
 // AddRow adds a new row to the table
@@ -225,16 +262,33 @@ func (t *table) AddRow(name string) Row {
 	t.Lock()
 	defer t.Unlock()
 
+	return t.addRow(name, false)
+}
+
+// addSpecialRow adds the header or the footer (or returns the existing one)
+// NB: locks t
+func (t *table) addSpecialRow(name string) *row {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.addRow(name, true)
+}
+
+// addRow adds a new row (or returns the existing header or footer). Body rows
+// cannot be named after the header or the footer.
+func (t *table) addRow(name string, special bool) *row {
+
 	// Normalize name
 	name = strings.ToLower(name)
 
 	// Check for header or footer
-	switch name {
-	case "header", "footer":
-		special, ok := t.headAndFoot[name]
-		if ok {
-			return special
+	var err error
+	if special {
+		if existing, ok := t.headAndFoot[name]; ok {
+			return existing
 		}
+	} else {
+		name, err = t.uniqueName(name)
 	}
 
 	// Create new row and append it to the table
@@ -243,16 +297,20 @@ func (t *table) AddRow(name string) Row {
 		Cells: []*cell{},
 		tref:  t,
 	}
+	if err != nil && t.Strict {
+		newRow.err = fmt.Errorf("AddRow: %s", err.Error())
+	}
 
 	// Add rows and their names
 	t.Rows = append(t.Rows, newRow)
 	t.RowNames = append(t.RowNames, name)
+	t.indexName(newRow, name)
 
 	// Remember header and footer
-	switch name {
-	case "header", "footer":
+	if special {
 		t.headAndFoot[name] = newRow
 	}
+	t.reindex(newRow)
 
 	return newRow
 }
@@ -327,9 +385,9 @@ func (t *table) Transform(trans func(v interface{}) interface{}, colnames ...str
 		}
 
 		for _, j := range colIdx {
-			if j <= len(t.Rows[i].Cells)-1 {
+			if j <= len(t.Rows[i].Cells)-1 && !isMissing(t.Rows[i].Cells[j].Value) {
 
-				switch reflect.TypeOf(t.Rows[i].Cells[j].Value).Kind() {
+				switch reflect.ValueOf(t.Rows[i].Cells[j].Value).Kind() {
 
 				case reflect.Slice:
 					slice := reflect.ValueOf(t.Rows[i].Cells[j].Value)
@@ -346,6 +404,8 @@ func (t *table) Transform(trans func(v interface{}) interface{}, colnames ...str
 			}
 		}
 	}
+	t.touch()
+
 	return nil
 }
 
@@ -376,10 +436,8 @@ func (t *table) GetRowByName(name string) (Row, error) {
 		return special, nil
 
 	default:
-		for i, rowname := range t.RowNames {
-			if rowname == name {
-				return t.Rows[i], nil
-			}
+		if rows := t.rowsByName(name); len(rows) > 0 {
+			return rows[0], nil
 		}
 		return nil, fmt.Errorf("GetRowByName: no such rowname '%s'", name)
 	}
@@ -415,16 +473,54 @@ func (t *table) Filter(filter func(values ...interface{}) bool, inplace, keepFoo
 	t.Lock()
 	defer t.Unlock()
 
+	fTable, err := t.filter(filter, inplace, keepFooter, false, columns...)
+	if err != nil {
+		return nil, fmt.Errorf("Filter: %s", err.Error())
+	}
+
+	return fTable, nil
+}
+
+// filter filters the rows of the table, optionally keeping the ancestors
+// of the matching rows
+func (t *table) filter(filter func(values ...interface{}) bool, inplace, keepFooter, keepAncestors bool, columns ...string) (*table, error) {
+
 	// Validate columns
 	if len(columns) == 0 {
-		return nil, fmt.Errorf("Filter: at least one column must be provided")
+		return nil, fmt.Errorf("at least one column must be provided")
 	}
 
 	colIdx := t.getColIdx(columns...)
 	if len(colIdx) == 0 {
-		return nil, fmt.Errorf("Filter: unknown columns")
+		return nil, fmt.Errorf("unknown columns")
 	}
 
+	// Matching rows (and their ancestors)
+	matching := map[*row]bool{}
+	for _, row := range t.Rows {
+		values := make([]interface{}, len(colIdx), len(colIdx))
+		for j, col := range colIdx {
+			if len(row.Cells)-1 < col {
+				continue
+			}
+			values[j] = row.Cells[col].Value
+		}
+		if !filter(values...) {
+			continue
+		}
+		matching[row] = true
+		for parent := row.parent; keepAncestors && parent != nil; parent = parent.parent {
+			matching[parent] = true
+		}
+	}
+
+	return t.selectRows(matching, inplace, keepFooter), nil
+}
+
+// selectRows keeps the header, the matching rows and optionally the footer
+// either inplace or in a new table referencing the rows
+func (t *table) selectRows(matching map[*row]bool, inplace, keepFooter bool) *table {
+
 	// Header and footer
 	header := t.headAndFoot["header"]
 	footer := t.headAndFoot["footer"]
@@ -442,33 +538,27 @@ func (t *table) Filter(filter func(values ...interface{}) bool, inplace, keepFoo
 			rows = append(rows, t.Rows[i])
 			rowNames = append(rowNames, t.RowNames[i])
 			headAndFoot["header"] = header
+			continue
 		}
 
 		// Optionally keep the footer
-		if row == footer && keepFooter {
-			rows = append(rows, t.Rows[i])
-			rowNames = append(rowNames, t.RowNames[i])
-			headAndFoot["footer"] = footer
-		}
-
-		// Run the filter
-		values := make([]interface{}, len(colIdx), len(colIdx))
-		for j, col := range colIdx {
-			if len(row.Cells)-1 < col {
-				continue
+		if row == footer {
+			if keepFooter {
+				rows = append(rows, t.Rows[i])
+				rowNames = append(rowNames, t.RowNames[i])
+				headAndFoot["footer"] = footer
 			}
-			values[j] = row.Cells[col].Value
+			continue
 		}
-		if filter(values...) {
+
+		// Keep matching rows
+		if matching[row] {
 			rows = append(rows, t.Rows[i])
 			rowNames = append(rowNames, t.RowNames[i])
 		}
 	}
 
-	fTable := t.tableFromRows(false, inplace, rows, rowNames, headAndFoot)
-
-	return fTable, nil
-
+	return t.tableFromRows(false, inplace, rows, rowNames, headAndFoot)
 }
 
 // FilterByRowNames is same as filter, only uses row names instead of column
@@ -568,27 +658,23 @@ func (t *table) RemoveRowsByName(names ...string) error {
 	footer := t.headAndFoot["footer"]
 
 	// Gather RowIds
-	seen := make(map[int]bool, len(t.Rows))
-	selected := make([]int, len(t.Rows))
-	j := 0
+	removed := make(map[string]bool, len(names))
 	for _, rmname := range names {
-		for i, name := range t.RowNames {
-			if strings.ToLower(rmname) == name {
-				if t.Rows[i] == header || t.Rows[i] == footer {
-					return fmt.Errorf("RemoveRowsByName: cannot remove header or footer")
-				}
-				if _, ok := seen[i]; ok {
-					continue
-				}
-				selected[j] = i
-				seen[i] = true
-				j++
+		removed[strings.ToLower(rmname)] = true
+	}
+
+	selected := []int{}
+	for i, name := range t.RowNames {
+		if removed[name] {
+			if t.Rows[i] == header || t.Rows[i] == footer {
+				return fmt.Errorf("RemoveRowsByName: cannot remove header or footer")
 			}
+			selected = append(selected, i)
 		}
 	}
 
 	// Remove rows
-	t.removeRows(selected[:j])
+	t.removeRows(selected)
 
 	return nil
 }
@@ -599,29 +685,199 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 	t.Lock()
 	defer t.Unlock()
 
+	t.render(dst, measureModified, modified, centered, template, columns...)
+}
+
+// render writes a rendered table into an io.Writer
+func (t *table) render(dst io.Writer, measureModified, modified, centered bool, template Template, columns ...string) {
+
+	// Prepare cells
+	measureRows, printRows, widths, spans, headRow, footRow, rowCount := t.prepareCells(measureModified, modified, template, columns...)
+
+	// Header groups
+	var tiers []headerTier
+	if headRow != -1 && template.FlattenHeaders() {
+		t.flattenHeader(measureRows[headRow], printRows[headRow], widths, columns...)
+	} else if headRow != -1 {
+		tiers = t.headerTiers(widths, columns...)
+	}
+
+	// Row groups
+	blocks, total := t.groupBlocks(widths, headRow, footRow, columns...)
+
+	// Set template widths
+	template.SetColumnWidths(widths)
+	template.SetDisplayOptions(centered)
+
+	// Prepare table slice
+	lines := []string{""}
+
+	// Title
+	if len(t.Titles) > 0 {
+		lines = append(lines, template.RenderTitles(t.Titles)...)
+	}
+
+	// Borders join the walls of neighbouring rows (in the order of rendering)
+	neighbours := []*Spans{}
+	if headRow != -1 {
+		neighbours = append(neighbours, &spans[headRow])
+	}
+	if blocks == nil {
+		for i := range measureRows {
+			if i != headRow && i != footRow && measureRows[i] != nil {
+				neighbours = append(neighbours, &spans[i])
+			}
+		}
+	}
+	groupHeaders, subtotals := make([]Spans, len(blocks)), make([]Spans, len(blocks))
+	for b, block := range blocks {
+		groupHeaders[b] = mergedSpans(widths)
+		neighbours = append(neighbours, &groupHeaders[b])
+		for _, i := range block.rows {
+			if measureRows[i] != nil {
+				neighbours = append(neighbours, &spans[i])
+			}
+		}
+		if block.mcells != nil {
+			subtotals[b].Cols = subtotals[b].columns(len(widths))
+			neighbours = append(neighbours, &subtotals[b])
+		}
+	}
+	footSpans := &Spans{}
+	if footRow != -1 {
+		footSpans = &spans[footRow]
+	}
+	linkSpans(append(neighbours, footSpans)...)
+
+	// Render header
+	for _, tier := range tiers {
+		template.SetSpans(tier.spans)
+		lines = append(lines, template.RenderHeader(tier.mcells, tier.pcells)...)
+	}
+	if headRow != -1 {
+		if len(tiers) > 0 {
+			spans[headRow].Above = tiers[len(tiers)-1].spans.Cols
+		}
+		template.SetSpans(spans[headRow])
+		lines = append(lines, template.RenderHeader(measureRows[headRow], printRows[headRow])...)
+	}
+
+	// Render rows
+	if blocks == nil {
+		rnr := 1
+		for i := range measureRows {
+			if i == headRow || i == footRow || measureRows[i] == nil {
+				continue
+			}
+			template.SetSpans(spans[i])
+			lines = append(lines, template.RenderRow(rnr, rowCount, measureRows[i], printRows[i])...)
+			rnr++
+		}
+	}
+
+	// Render row groups
+	for b, block := range blocks {
+		if b > 0 {
+			lines = append(lines, template.RenderGroupSeparator()...)
+		}
+
+		template.SetSpans(groupHeaders[b])
+		lines = append(lines, template.RenderGroupHeader(block.title)...)
+
+		for rnr, i := range block.rows {
+			if measureRows[i] == nil {
+				continue
+			}
+			template.SetSpans(spans[i])
+			lines = append(lines, template.RenderRow(rnr+1, len(block.rows), measureRows[i], printRows[i])...)
+		}
+
+		if block.mcells != nil {
+			template.SetSpans(subtotals[b])
+			lines = append(lines, template.RenderSubtotal(block.mcells, block.pcells)...)
+		}
+	}
+
+	// Render footer (or the grand total of the row groups)
+	template.SetSpans(*footSpans)
+	if footRow != -1 {
+		lines = append(lines, template.RenderFooter(measureRows[footRow], printRows[footRow])...)
+	} else if total != nil {
+		lines = append(lines, template.RenderFooter(total, total)...)
+	} else {
+		lines = append(lines, template.RenderFooter([]string{}, []string{})...)
+	}
+	template.SetSpans(Spans{})
+
+	// Render Footnotes
+	if len(t.Footnotes) > 0 {
+		lines = append(lines, template.RenderFootnotes(t.Footnotes)...)
+	}
+
+	// Write to destination
+	dst.Write([]byte(strings.Join(lines, "\n")))
+
+}
+
+// prepareCells formats and modifies all the cells of the selected columns and
+// measures the column widths. It returns the measured and printable cells of
+// every row, the column widths, the positions of the header and footer rows
+// (-1 if absent) and the count of regular rows.
+func (t *table) prepareCells(measureModified, modified bool, template Template, columns ...string) (measureRows, printRows [][]string, widths []int, spans []Spans, headRow, footRow, rowCount int) {
+
 	// Header and footer info
-	headRow, footRow := -1, -1
+	headRow, footRow = -1, -1
 	header, _ := t.headAndFoot["header"]
 	footer, _ := t.headAndFoot["footer"]
 
 	// Get relevant columns
 	colIdx := t.getColIdx(columns...)
 
+	// Conditional styles and visualizations
+	cellStyles, rowStyles := t.evalRules()
+	profile := ProfileNone
+	if len(cellStyles) > 0 || len(rowStyles) > 0 || len(t.renderers) > 0 {
+		profile = resolveProfile(template.Theme().Profile)
+	}
+	contexts := t.columnContexts(profile)
+
+	// Tree tables
+	tree := t.layoutTree()
+	treeAggregates := t.treeAggregates()
+
+	// Rows shorter than the table have missing cells
+	columnCount := t.columnCount()
+
 	// Final rows
-	measureRows := [][]string{}
-	printRows := [][]string{}
+	measureRows = [][]string{}
+	printRows = [][]string{}
+
+	// Merged cells
+	spans = []Spans{}
+	covers := map[int]rowCover{}
+	wide := []spannedCell{}
+	body := []int{}
 
 	// Walk through rows
-	rowCount := 0
-	widths := []int{}
+	rowCount = 0
+	widths = []int{}
 	for i, row := range t.Rows {
 
+		// Collapsed tree rows are not rendered
+		if tree != nil && tree.hidden[row] {
+			measureRows = append(measureRows, nil)
+			printRows = append(printRows, nil)
+			spans = append(spans, Spans{})
+			continue
+		}
+
 		if t.Rows[i] == header {
 			headRow = i
 		} else if t.Rows[i] == footer {
 			footRow = i
 		} else {
 			rowCount++
+			body = append(body, i)
 		}
 
 		// Relevant columns
@@ -629,24 +885,46 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 		if len(colIdx) != 0 {
 			rangeVar = colIdx
 		} else {
-			for k := range row.Cells {
+			for k := 0; k < columnCount; k++ {
 				rangeVar = append(rangeVar, k)
 			}
 		}
 
 		measureRow := []string{}
 		printRow := []string{}
+		rowSpans := Spans{}
+		spanned := map[int]rowCover{} // Covers starting below this row
+		merged := 0
 
 		// Walk through all the columns of a row
 		for j, jcol := range rangeVar {
 
-			// Ignore overflowing cells
-			if jcol >= len(row.Cells) {
+			// Cells merged into cells above or to the left are left empty
+			covered := true
+			if cover, ok := covers[j]; ok && cover.rows > 0 && row != header && row != footer {
+				cover.rows--
+				covers[j] = cover
+				rowSpans.add(cover.cols, true, cover.rows > 0)
+			} else if merged > 0 {
+				merged--
+				rowSpans.add(0, false, false)
+			} else {
+				covered = false
+			}
+			if covered {
+				if len(widths) < j+1 {
+					widths = append(widths, 0)
+				}
+				measureRow = append(measureRow, "")
+				printRow = append(printRow, "")
 				continue
 			}
 
-			// Select cell
-			jcell := row.Cells[jcol]
+			// Select cell (cells of short rows are missing)
+			jcell := &cell{Mutex: &sync.Mutex{}, Value: Missing}
+			if jcol < len(row.Cells) {
+				jcell = row.Cells[jcol]
+			}
 
 			// Determine formats and modifiers
 			jcell.Lock()
@@ -654,34 +932,81 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 				widths = append(widths, 0)
 			}
 
-			if _, ok := t.Formats[j]; !ok {
-				t.Formats[j] = "%v"
+			// Spans (only over the rendered columns)
+			span := 1
+			for k := j + 1; jcell.ColSpan > 1 && k < len(rangeVar) && rangeVar[k] > jcol && rangeVar[k] < jcol+jcell.ColSpan; k++ {
+				span++
+			}
+			merged = span - 1
+			rowSpan := jcell.RowSpan > 1 && row != header && row != footer
+			rowSpans.add(span, false, rowSpan)
+			for k := j; rowSpan && k < j+span; k++ {
+				cover := rowCover{rows: jcell.RowSpan - 1}
+				if k == j {
+					cover.cols = span
+				}
+				spanned[k] = cover
 			}
 
-			format := t.Formats[j]
+			format, ok := t.Formats[j]
+			if !ok {
+				format = "%v"
+			}
 			if jcell.modFunc == nil {
 				jcell.modFunc = func(v interface{}) interface{} { return v }
 			}
 
+			// Parent rows of tree tables may show aggregates of their children
+			value := jcell.Value
+			if aggregated, ok := tree.aggregate(row, jcol, treeAggregates[jcol]); ok {
+				value = aggregated
+			}
+
 			// Prepare formated and modified values
 			var valueNorm string
 			var valueMod string
 
-			switch reflect.TypeOf(jcell.Value).Kind() {
+			nested, isNested := value.(Table)
+			formatted, typeStyle, isFormatted := t.formatValue(jcol, j, value)
+			kind := reflect.Invalid
+			if value != nil {
+				kind = reflect.TypeOf(value).Kind()
+			}
+			switch {
 
-			case reflect.String:
+			// Missing values are only shown in body rows
+			case isMissing(value):
+				if row != header && row != footer {
+					valueNorm = t.placeholder(jcol)
+					valueMod = valueNorm
+				}
+
+			// Nested tables are rendered as blocks
+			case isNested:
+				valueNorm, valueMod = t.renderNested(nested, measureModified, modified)
+
+			// Formatters take precedence over formats
+			case isFormatted:
+				valueNorm = formatted
+				mod := jcell.modFunc(value)
+				valueMod = fmt.Sprintf(format, mod)
+				if formattedMod, _, ok := t.formatValue(jcol, j, mod); ok {
+					valueMod = formattedMod
+				}
+
+			case kind == reflect.String:
 				valueNormSlice := []string{}
 				valueModSlice := []string{}
-				value, _ := jcell.Value.(string)
-				for _, part := range strings.Split(value, "\n") {
+				text, _ := value.(string)
+				for _, part := range strings.Split(text, "\n") {
 					valueNormSlice = append(valueNormSlice, fmt.Sprintf(format, part))
 					valueModSlice = append(valueModSlice, fmt.Sprintf(format, jcell.modFunc(part)))
 				}
 				valueNorm = strings.Join(valueNormSlice, "\n")
 				valueMod = strings.Join(valueModSlice, "\n")
 
-			case reflect.Slice:
-				slice := reflect.ValueOf(jcell.Value)
+			case kind == reflect.Slice:
+				slice := reflect.ValueOf(value)
 				valueNormSlice := []string{}
 				valueModSlice := []string{}
 				for i := 0; i < slice.Len(); i++ {
@@ -692,32 +1017,46 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 				valueMod = strings.Join(valueModSlice, "\n")
 
 			default:
-				valueNorm = fmt.Sprintf(format, jcell.Value)
-				valueMod = fmt.Sprintf(format, jcell.modFunc(jcell.Value))
+				valueNorm = fmt.Sprintf(format, value)
+				valueMod = fmt.Sprintf(format, jcell.modFunc(value))
 			}
 
 			jcell.ModVal = valueMod
 
-			if measureModified {
-				measureRow = append(measureRow, valueMod)
+			// Visualizations are always measured
+			measureMod := measureModified
+			if renderer, ok := t.renderers[jcol]; ok && modified && row != header && row != footer {
+				valueMod = renderer(value, valueMod, contexts[jcol])
+				measureMod = true
+			}
+
+			// Tree glyphs
+			if prefix := tree.prefix(row, j); prefix != "" {
+				valueNorm = prefix + valueNorm
+				valueMod = prefix + valueMod
+			}
+
+			if measureMod {
+				measureRow = append(measureRow, stripANSI(valueMod))
 			} else {
 				measureRow = append(measureRow, valueNorm)
 			}
 			if modified {
-				printRow = append(printRow, valueMod)
+				style := typeStyle.Merge(rowStyles[i]).Merge(cellStyles[i][jcol])
+				printRow = append(printRow, style.Sprint(valueMod, profile))
 			} else {
 				printRow = append(printRow, valueNorm)
 			}
 
 			// Remember column widths
-			if measureModified {
-				if length := utf8.RuneCountInString(valueMod); length > widths[j] {
-					widths[j] = length
-				}
-			} else {
-				if length := utf8.RuneCountInString(valueNorm); length > widths[j] {
-					widths[j] = length
-				}
+			length := blockWidth(valueNorm)
+			if measureMod {
+				length = blockWidth(measureRow[len(measureRow)-1])
+			}
+			if span > 1 {
+				wide = append(wide, spannedCell{pos: j, span: span, length: length})
+			} else if length > widths[j] {
+				widths[j] = length
 			}
 			if widthOverride, ok := t.WidthOverrides[j]; ok {
 				widths[j] = widthOverride + 2
@@ -727,52 +1066,30 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 			jcell.Unlock()
 		}
 
+		for k, cover := range spanned {
+			covers[k] = cover
+		}
+
 		measureRows = append(measureRows, measureRow)
 		printRows = append(printRows, printRow)
+		spans = append(spans, rowSpans)
 	}
 
-	// Set template widths
-	template.SetColumnWidths(widths)
-	template.SetDisplayOptions(centered)
-
-	// Prepare table slice
-	lines := []string{""}
-
-	// Title
-	if len(t.Titles) > 0 {
-		lines = append(lines, template.RenderTitles(t.Titles)...)
-	}
-
-	// Render header
-	if headRow != -1 {
-		lines = append(lines, template.RenderHeader(measureRows[headRow], printRows[headRow])...)
-	}
+	// Fit cells spanning several columns
+	distributeSpans(widths, wide)
+	closeRowSpans(spans, body)
 
-	// Render rows
-	rnr := 1
-	for i := range measureRows {
-		if i == headRow || i == footRow {
-			continue
+	// Tree columns are left-aligned
+	if tree != nil && len(widths) > 0 {
+		for _, i := range body {
+			if len(measureRows[i]) > 0 {
+				measureRows[i][0] = padLines(measureRows[i][0], widths[0])
+				printRows[i][0] = padLines(printRows[i][0], widths[0])
+			}
 		}
-		lines = append(lines, template.RenderRow(rnr, rowCount, measureRows[i], printRows[i])...)
-		rnr++
 	}
 
-	// Render footer
-	if footRow != -1 {
-		lines = append(lines, template.RenderFooter(measureRows[footRow], printRows[footRow])...)
-	} else {
-		lines = append(lines, template.RenderFooter([]string{}, []string{})...)
-	}
-
-	// Render Footnotes
-	if len(t.Footnotes) > 0 {
-		lines = append(lines, template.RenderFootnotes(t.Footnotes)...)
-	}
-
-	// Write to destination
-	dst.Write([]byte(strings.Join(lines, "\n")))
-
+	return measureRows, printRows, widths, spans, headRow, footRow, rowCount
 }
 
 // Marshals the table to json including all meta information (row names,
@@ -781,11 +1098,15 @@ func (t *table) Render(dst io.Writer, measureModified, modified, centered bool,
 func (t *table) MarshalToRichJSON(dst io.Writer) (int, error) {
 
 	// Render to create modvals
-	t.Render(bytes.NewBuffer([]byte{}), false, true, true, LoadTemplate("classic"))
+	t.Render(bytes.NewBuffer([]byte{}), false, true, true, tmplClassic())
 
 	t.Lock()
 	defer t.Unlock()
 
+	// Tree structure and missing values
+	t.parentIndices()
+	t.markMissing()
+
 	// Marshal
 	jsoned, err := json.Marshal(t)
 	if err != nil {
@@ -798,12 +1119,31 @@ func (t *table) MarshalToRichJSON(dst io.Writer) (int, error) {
 
 // MarshalToVanillaJSON marshals the table as a simple list of objects,
 // one object per row, i.e. [{col1: val1, col2: val2},{col1: val3, col2: val4}].
-// It does not preserve modifiers, row names and so on.
+// It does not preserve modifiers, row names and so on. Nested tables are
+// marshaled as lists of objects as well ("<cycle>" if they contain themselves).
 // NB: locks t
 func (t *table) MarshalToVanillaJSON(dst io.Writer) (int, error) {
 	t.Lock()
 	defer t.Unlock()
 
+	rows := t.vanillaRows(map[*table]bool{t: true})
+
+	// Marshal
+	jsoned, err := json.Marshal(rows)
+	if err != nil {
+		return 0, fmt.Errorf("MarshalToVanillaJSON: could not marshal to JSON: %s", err.Error())
+	}
+
+	// Write to destination
+	return dst.Write(jsoned)
+
+}
+
+// vanillaRows converts the (locked) table to a list of objects, one object per
+// row (see MarshalToVanillaJSON). Enclosing holds t and the tables it is
+// nested in.
+func (t *table) vanillaRows(enclosing map[*table]bool) []map[string]interface{} {
+
 	// Header and footer
 	header := t.headAndFoot["header"]
 	footer := t.headAndFoot["footer"]
@@ -825,12 +1165,18 @@ func (t *table) MarshalToVanillaJSON(dst io.Writer) (int, error) {
 		}
 
 		rows[i] = map[string]interface{}{}
-		for j, cell := range row.Cells {
+		for j := 0; j < len(row.Cells) || (header != nil && j < len(header.Cells)); j++ {
 			colname := fmt.Sprintf("COL_%d", j)
 			if header != nil && len(header.Cells)-1 >= j {
 				colname = fmt.Sprintf("%v", header.Cells[j].Value)
 			}
-			rows[i][colname] = cell.Value
+
+			// Missing values are marshaled as null
+			if j >= len(row.Cells) {
+				rows[i][colname] = nil
+				continue
+			}
+			rows[i][colname] = vanillaValue(row.Cells[j].Value, enclosing)
 		}
 	}
 
@@ -847,15 +1193,7 @@ func (t *table) MarshalToVanillaJSON(dst io.Writer) (int, error) {
 		}
 	}
 
-	// Marshal
-	jsoned, err := json.Marshal(rows)
-	if err != nil {
-		return 0, fmt.Errorf("MarshalToVanillaJSON: could not marshal to JSON: %s", err.Error())
-	}
-
-	// Write to destination
-	return dst.Write(jsoned)
-
+	return rows
 }
 
 // removeRows removes a set of rows from the trable
@@ -875,6 +1213,7 @@ func (t *table) removeRows(selected []int) {
 			t.RowNames = t.RowNames[:nth]
 		}
 	}
+	t.restructure()
 
 }
 
@@ -893,16 +1232,37 @@ func (t *table) tableFromRows(lock, inplace bool, rows []*row, rowNames []string
 		t.Rows = rows
 		t.RowNames = rowNames
 		t.headAndFoot = hf
+		t.restructure()
 		fTable = t
 	} else {
+
+		// Indexes of the new table are maintained separately
+		indexed := map[int]bool{}
+		for col := range t.indexed {
+			indexed[col] = true
+		}
+
 		fTable = &table{
-			Mutex:       &sync.Mutex{},
-			Rows:        rows,
-			RowNames:    rowNames,
-			Formats:     t.Formats,
-			Titles:      t.Titles,
-			Footnotes:   t.Footnotes,
-			headAndFoot: hf,
+			Mutex:        &sync.Mutex{},
+			Rows:         rows,
+			RowNames:     rowNames,
+			Formats:      t.Formats,
+			Titles:       t.Titles,
+			Footnotes:    t.Footnotes,
+			Strict:       t.Strict,
+			Rules:        t.Rules,
+			HeaderGroups: t.HeaderGroups,
+			renderers:    t.renderers,
+			formatters:   t.formatters,
+			typeFormats:  t.typeFormats,
+			placeholders: t.placeholders,
+			grouping:     t.grouping,
+			tree:         t.tree,
+			nested:       t.nested,
+			keyIndexes:   map[string]*keyIndex{},
+			indexed:      indexed,
+			revision:     t.revision,
+			headAndFoot:  hf,
 		}
 	}
 
@@ -931,13 +1291,10 @@ func (t *table) getColnameIndex(colname string, lockTable, lockHead bool) int {
 		defer t.Unlock()
 	}
 
-	// Default: no such column
-	index := -1
-
 	// Check the header
 	header, ok := t.headAndFoot["header"]
 	if !ok {
-		return index
+		return -1
 	}
 
 	// Find colname index
@@ -946,18 +1303,7 @@ func (t *table) getColnameIndex(colname string, lockTable, lockHead bool) int {
 		defer header.Unlock()
 	}
 
-	for i, hcell := range header.Cells {
-
-		vs, ok := hcell.Value.(string)
-		if !ok {
-			continue
-		}
-		if strings.ToLower(vs) == strings.ToLower(colname) {
-			return i
-		}
-	}
-
-	return index
+	return t.columnPosition(header, colname)
 }
 
 // Insert inserts some values into the row
@@ -969,6 +1315,18 @@ func (r *row) Insert(vals ...interface{}) Row {
 		return r
 	}
 
+	if limit := r.columnLimit(); limit != -1 && len(r.Cells)+len(vals) > limit {
+		r.record(fmt.Errorf("Insert: too many values (%d cells, %d columns)", len(r.Cells)+len(vals), limit))
+	}
+
+	r.insert(vals...)
+
+	return r
+}
+
+// insert appends cells to the row
+func (r *row) insert(vals ...interface{}) {
+
 	// Insert cells
 	//
 	// NB: If no header has been set, then all the values are going to be shown
@@ -980,8 +1338,7 @@ func (r *row) Insert(vals ...interface{}) Row {
 			Value: vals[i],
 		})
 	}
-
-	return r
+	r.changed()
 }
 
 // Change changes a row cell's value
@@ -989,32 +1346,10 @@ func (r *row) Change(colname string, value interface{}) Row {
 	r.Lock()
 	defer r.Unlock()
 
-	colname = strings.ToLower(colname)
-
-	// Header and footer rows
-	header := r.tref.headAndFoot["header"]
-	footer := r.tref.headAndFoot["footer"]
-
-	// Find relevant column
-	var index int
-	switch r {
-	case header, footer:
-		index = r.tref.getColnameIndex(colname, true, false)
-	default:
-		index = r.tref.getColnameIndex(colname, true, true)
-	}
-
-	if index == -1 {
-		return r
+	if err := r.set(colname, value); err != nil {
+		r.record(fmt.Errorf("Change: %s", err.Error()))
 	}
 
-	// Change the value
-	rcell := r.Cells[index]
-	rcell.Lock()
-	rcell.Value = value
-	rcell.modFunc = func(v interface{}) interface{} { return v }
-	rcell.Unlock()
-
 	return r
 }
 
@@ -1027,33 +1362,20 @@ func (r *row) Modify(modifier func(interface{}) interface{}, colnames ...string)
 		return r
 	}
 
-	// Header and footer rows
-	header := r.tref.headAndFoot["header"]
-	footer := r.tref.headAndFoot["footer"]
-
-	// Modify all relevant cells
-	for _, colname := range colnames {
-
-		colname = strings.ToLower(colname)
-
-		var index int
+	if err := r.modify(modifier, true, colnames...); err != nil {
+		r.record(fmt.Errorf("Modify: %s", err.Error()))
+	}
 
-		switch r {
-		case header, footer:
-			index = r.tref.getColnameIndex(colname, true, false)
-		default:
-			index = r.tref.getColnameIndex(colname, true, true)
-		}
+	return r
+}
 
-		if index == -1 {
-			continue
+// blockWidth returns the width of the widest line of a (multi-line) string
+func blockWidth(s string) int {
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		if length := utf8.RuneCountInString(line); length > width {
+			width = length
 		}
-
-		rcell := r.Cells[index]
-		rcell.Lock()
-		rcell.modFunc = modifier
-		rcell.Unlock()
 	}
-
-	return r
+	return width
 }
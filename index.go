@@ -0,0 +1,242 @@
+package lentele
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// keyIndex maps the values of key columns to the body rows having them
+type keyIndex struct {
+	columns  []int             // Key columns
+	revision uint32            // Revision of the table the index was built at
+	rows     map[string][]*row // Rows by key
+	keys     map[*row]string   // Keys by row
+}
+
+// columnIndex maps lowercase column names to their positions in the header
+type columnIndex struct {
+	header    *row           // Indexed header
+	revision  uint32         // Revision of the header the index was built at
+	positions map[string]int // Positions of the first columns having the names
+}
+
+// touch marks a change of the table's rows or values, invalidating its key
+// indexes (and the key indexes of the tables sharing its rows)
+func (t *table) touch() {
+	if t != nil && t.revision != nil {
+		atomic.AddUint32(t.revision, 1)
+	}
+}
+
+// restructure marks a change of the table's rows (added, removed or moved
+// rows), invalidating all its indexes
+func (t *table) restructure() {
+	t.rowIndex = nil
+	t.touch()
+}
+
+// touch marks a change of the row's values
+func (r *row) touch() {
+	atomic.AddUint32(&r.revision, 1)
+}
+
+// changed updates the key indexes of the row's table after a change of the
+// row's values
+// NB: locks the table
+func (r *row) changed() {
+	r.touch()
+
+	if t := r.tref; t != nil {
+		t.Lock()
+		t.reindex(r)
+		t.Unlock()
+	}
+}
+
+// indexKey returns the key of values
+func indexKey(values ...interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprintf("%v", v)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// keyIndex returns the index of the key columns, rebuilding it if the table
+// has changed since it was built
+func (t *table) keyIndex(colIdx []int) *keyIndex {
+
+	name := fmt.Sprintf("%v", colIdx)
+	revision := atomic.LoadUint32(t.revision)
+	if index, ok := t.keyIndexes[name]; ok && index.revision == revision {
+		return index
+	}
+
+	index := &keyIndex{
+		columns:  colIdx,
+		revision: revision,
+		rows:     map[string][]*row{},
+		keys:     map[*row]string{},
+	}
+	for _, r := range t.bodyRows() {
+		index.add(r)
+	}
+	t.keyIndexes[name] = index
+
+	return index
+}
+
+// add adds a row to the index
+func (index *keyIndex) add(r *row) {
+	key := indexKey(rowValues(r, index.columns)...)
+	index.rows[key] = append(index.rows[key], r)
+	index.keys[r] = key
+}
+
+// remove removes a row from the index
+func (index *keyIndex) remove(r *row) {
+	key, ok := index.keys[r]
+	if !ok {
+		return
+	}
+
+	rows := index.rows[key]
+	for i := range rows {
+		if rows[i] == r {
+			index.rows[key] = append(rows[:i:i], rows[i+1:]...)
+			break
+		}
+	}
+	if len(index.rows[key]) == 0 {
+		delete(index.rows, key)
+	}
+	delete(index.keys, r)
+}
+
+// reindex touches t and updates the key indexes, that were up to date before
+// the change, with the changed (or added) body rows instead of rebuilding them
+func (t *table) reindex(rows ...*row) {
+
+	if t.revision == nil {
+		return
+	}
+
+	revision := atomic.AddUint32(t.revision, 1)
+	previous := revision - 1
+
+	for _, index := range t.keyIndexes {
+		if index.revision != previous {
+			continue
+		}
+		for _, r := range rows {
+			index.remove(r)
+			if r != t.headAndFoot["header"] && r != t.headAndFoot["footer"] {
+				index.add(r)
+			}
+		}
+		index.revision = revision
+	}
+}
+
+// rowsByName returns the rows having the (lowercase) name, rebuilding the
+// index of row names if the rows have changed
+func (t *table) rowsByName(name string) []*row {
+
+	if t.rowIndex == nil {
+		t.rowIndex = map[string][]*row{}
+		for i, rowname := range t.RowNames {
+			t.rowIndex[rowname] = append(t.rowIndex[rowname], t.Rows[i])
+		}
+	}
+
+	return t.rowIndex[name]
+}
+
+// indexName adds an appended row to the index of row names (if it is built)
+func (t *table) indexName(r *row, name string) {
+	if t.rowIndex != nil {
+		t.rowIndex[name] = append(t.rowIndex[name], r)
+	}
+}
+
+// columnPosition returns the position of the column in the header (-1 if there
+// is no such column), rebuilding the index of column names if the header has
+// changed. The header has to be locked (or owned) by the caller.
+func (t *table) columnPosition(header *row, colname string) int {
+
+	revision := atomic.LoadUint32(&header.revision)
+	index := t.colIndex
+	if index == nil || index.header != header || index.revision != revision {
+		index = &columnIndex{header: header, revision: revision, positions: map[string]int{}}
+		for i, hcell := range header.Cells {
+			vs, ok := hcell.Value.(string)
+			if !ok {
+				continue
+			}
+			if _, ok := index.positions[strings.ToLower(vs)]; !ok {
+				index.positions[strings.ToLower(vs)] = i
+			}
+		}
+		t.colIndex = index
+	}
+
+	if position, ok := index.positions[strings.ToLower(colname)]; ok {
+		return position
+	}
+	return -1
+}
+
+// SetIndex maintains indexes of the columns' values used by FindRows
+// NB: locks t
+func (t *table) SetIndex(colnames ...string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(colnames) == 0 {
+		return fmt.Errorf("SetIndex: provide at least one column name")
+	}
+
+	for _, colname := range colnames {
+		col := t.getColnameIndex(colname, false, false)
+		if col == -1 {
+			return fmt.Errorf("SetIndex: no such column '%s'", colname)
+		}
+		t.indexed[col] = true
+		t.keyIndex([]int{col})
+	}
+
+	return nil
+}
+
+// FindRows returns the body rows whose value of the column is (deeply) equal
+// to the value. Indexed columns (see SetIndex) are looked up in the index,
+// other columns are scanned.
+// NB: locks t
+func (t *table) FindRows(column string, value interface{}) ([]Row, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	col := t.getColnameIndex(column, false, false)
+	if col == -1 {
+		return nil, fmt.Errorf("FindRows: no such column '%s'", column)
+	}
+
+	var candidates []*row
+	if t.indexed[col] {
+		candidates = t.keyIndex([]int{col}).rows[indexKey(value)]
+	} else {
+		candidates = t.bodyRows()
+	}
+
+	// Rows of equally printed values (e.g. 1 and "1") are not matches
+	found := []Row{}
+	for _, r := range candidates {
+		if reflect.DeepEqual(cellValue(r, col), value) {
+			found = append(found, r)
+		}
+	}
+
+	return found, nil
+}
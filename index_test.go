@@ -0,0 +1,153 @@
+package lentele
+
+import (
+	"fmt"
+	"testing"
+)
+
+// buildIndexedTable builds a table of hosts with named rows
+func buildIndexedTable() Table {
+	table := New("Host", "Zone", "Load")
+	for i := 0; i < 10; i++ {
+		table.AddRow(fmt.Sprintf("web%d", i)).Insert(fmt.Sprintf("web%d", i), fmt.Sprintf("zone%d", i%3), i)
+	}
+
+	return table
+}
+
+// loadFilter filters the body rows by their loads
+func loadFilter(match func(load int) bool) func(values ...interface{}) bool {
+	return func(values ...interface{}) bool {
+		load, ok := values[0].(int)
+		return ok && match(load)
+	}
+}
+
+func TestRowNameIndex(t *testing.T) {
+
+	table := buildIndexedTable()
+	if web3, err := table.GetRowByName("WEB3"); err != nil || cellValue(web3.(*row), 2) != 3 {
+		t.Errorf("TestRowNameIndex: could not find row 'web3'")
+	}
+
+	// Removed rows
+	if err := table.RemoveRowsByName("web3", "web4", "web3"); err != nil {
+		t.Fatalf("TestRowNameIndex: could not remove rows: %s", err.Error())
+	}
+	if _, err := table.GetRowByName("web3"); err == nil {
+		t.Errorf("TestRowNameIndex: removed rows should not be found")
+	}
+	if err := table.RemoveRows(1); err != nil {
+		t.Fatalf("TestRowNameIndex: could not remove rows: %s", err.Error())
+	}
+	if _, err := table.GetRowByName("web0"); err == nil {
+		t.Errorf("TestRowNameIndex: removed rows should not be found")
+	}
+
+	// Filtered rows
+	table.Filter(loadFilter(func(load int) bool { return load > 5 }), true, false, "Load")
+	if _, err := table.GetRowByName("web5"); err == nil {
+		t.Errorf("TestRowNameIndex: filtered rows should not be found")
+	}
+
+	// Added rows
+	table.AddRow("web10").Insert("web10", "zone1", 10)
+	if web10, err := table.GetRowByName("web10"); err != nil || cellValue(web10.(*row), 2) != 10 {
+		t.Errorf("TestRowNameIndex: could not find row 'web10'")
+	}
+
+	if names := table.GetRowNames(); len(names) != 6 {
+		t.Errorf("TestRowNameIndex: unexpected row names %v", names)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+
+	table := buildIndexedTable()
+	header, _ := table.GetRowByName("header")
+
+	// Renamed columns
+	header.Change("Zone", "Region")
+	web1, _ := table.GetRowByName("web1")
+	web1.Change("Region", "zone9")
+	web1.Change("Zone", "zone8")
+	if value := cellValue(web1.(*row), 1); value != "zone9" {
+		t.Errorf("TestColumnIndex: expected zone9, got %v", value)
+	}
+}
+
+func TestFindRows(t *testing.T) {
+
+	table := buildIndexedTable()
+	if err := table.SetIndex("Unknown"); err == nil {
+		t.Errorf("TestFindRows: unknown columns should fail")
+	}
+	if _, err := table.FindRows("Unknown", 1); err == nil {
+		t.Errorf("TestFindRows: unknown columns should fail")
+	}
+
+	find := func(column string, value interface{}) []interface{} {
+		rows, err := table.FindRows(column, value)
+		if err != nil {
+			t.Fatalf("TestFindRows: could not find rows: %s", err.Error())
+		}
+		hosts := []interface{}{}
+		for _, r := range rows {
+			hosts = append(hosts, cellValue(r.(*row), 0))
+		}
+		return hosts
+	}
+
+	// Scans and index lookups match
+	scanned := fmt.Sprint(find("Zone", "zone1"))
+	if err := table.SetIndex("Zone", "Load"); err != nil {
+		t.Fatalf("TestFindRows: could not set index: %s", err.Error())
+	}
+	if indexed := fmt.Sprint(find("Zone", "zone1")); indexed != scanned || indexed != "[web1 web4 web7]" {
+		t.Errorf("TestFindRows: expected %s, got %s", scanned, indexed)
+	}
+
+	// Equally printed values do not match
+	if found := find("Load", "1"); len(found) != 0 {
+		t.Errorf("TestFindRows: unexpected rows %v", found)
+	}
+
+	// Changed, added, removed and filtered rows
+	web4, _ := table.GetRowByName("web4")
+	web4.Change("Zone", "zone2")
+	table.AddRow("web10").Insert("web10", "zone1", 10)
+	table.RemoveRowsByName("web1")
+	filtered, _ := table.Filter(loadFilter(func(load int) bool { return load < 10 }), false, false, "Load")
+
+	if found := fmt.Sprint(find("Zone", "zone1")); found != "[web7 web10]" {
+		t.Errorf("TestFindRows: expected [web7 web10], got %s", found)
+	}
+	if rows, _ := filtered.FindRows("Zone", "zone1"); len(rows) != 1 {
+		t.Errorf("TestFindRows: expected 1 row in the filtered table, got %d", len(rows))
+	}
+
+	// Changes of shared rows invalidate the indexes of both tables
+	web7, _ := filtered.GetRowByName("web7")
+	web7.Change("Zone", "zone0")
+	if rows, _ := filtered.FindRows("Zone", "zone1"); len(rows) != 0 {
+		t.Errorf("TestFindRows: expected no rows in the filtered table, got %d", len(rows))
+	}
+	if found := fmt.Sprint(find("Zone", "zone1")); found != "[web10]" {
+		t.Errorf("TestFindRows: expected [web10], got %s", found)
+	}
+}
+
+func TestFilteredIndexes(t *testing.T) {
+
+	table := buildIndexedTable()
+	table.SetIndex("Zone")
+	filtered, _ := table.Filter(loadFilter(func(load int) bool { return load < 5 }), false, false, "Load")
+
+	// Indexes set on a filtered table are not set on the source table
+	if err := filtered.SetIndex("Host"); err != nil {
+		t.Fatalf("TestFilteredIndexes: could not set index: %s", err.Error())
+	}
+	if source := original(table); source.indexed[0] || !source.indexed[1] {
+		t.Errorf("TestFilteredIndexes: unexpected indexes of the source table %v", source.indexed)
+	}
+}
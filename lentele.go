@@ -38,22 +38,119 @@ type Table interface {
 	// Adds a row to the table.
 	//
 	// If a unique name is provided, then the table can be searched for rows by names.
-	// Rownames are case insensitive.
+	// Rownames are case insensitive. Uniqueness is only enforced in strict mode
+	// (see SetStrict), where rows with used names are added without a name.
+	//
+	// The names "header" and "footer" are reserved for AddHeader/AddFooter:
+	// rows added with a reserved name are not named.
 	AddRow(name string) Row
 
+	// AddNamedRow adds a row with a unique name. Fails if the name is blank,
+	// reserved or already used.
+	AddNamedRow(name string) (Row, error)
+
+	// SetStrict enables the strict mode, which enforces unique row names and
+	// makes the chainable row methods record their errors (see Row.Err).
+	// Fails if the table already has duplicate row names.
+	SetStrict(strict bool) error
+
 	// SetFormat sets a column's format and returns an error if no such column
 	// exists. If no format is specified, then "%v" is going to be used.
 	SetFormat(format string, colnames ...string) error
 
+	// SetFormatter formats the numeric values of columns at render time (see
+	// Locale), leaving the raw values intact. Setting a nil formatter removes
+	// the formatter. Fails if any of the columns is unknown.
+	SetFormatter(formatter Formatter, colnames ...string) error
+
+	// AddTypeFormat formats (and styles) the values matched by the type
+	// format's predicate in columns having neither a format nor a formatter.
+	// Type formats of the table take precedence over the ones shared by all
+	// tables (RegisterTypeFormat).
+	AddTypeFormat(format *TypeFormat) error
+
+	// ClearTypeFormats removes the type formats of the table
+	ClearTypeFormats()
+
+	// SetPlaceholder sets the placeholder of missing values (see Missing) for
+	// the columns or, if no columns are provided, for the whole table
+	SetPlaceholder(placeholder string, colnames ...string) error
+
+	// FillMissing replaces missing values of body rows with a value or, if
+	// the fill is an aggregate (e.g. Mean), with the aggregate of the values
+	// that are not missing. Fills all the columns if none are provided.
+	FillMissing(fill interface{}, colnames ...string) error
+
+	// DropMissing removes the body rows having missing values in any of the
+	// columns (all the columns if none are provided)
+	DropMissing(colnames ...string) error
+
 	// SetColumnWidth overrides column width calculations with static values
 	SetColumnWidth(width int, colnames ...string) error
 
+	// AddStyleRule styles the cells of a column whose raw values satisfy the
+	// predicate. Rules are evaluated at render time (modified view only).
+	AddStyleRule(column string, predicate func(v interface{}) bool, style Style) error
+
+	// AddStyleRules adds conditional formatting rules, e.g. RangeRule, TopRule,
+	// BottomRule, NegativeRule or RegexRule. Rules can style whole rows
+	// (StyleRule.Row).
+	AddStyleRules(rules ...*StyleRule) error
+
+	// ClearStyleRules removes all the style rules
+	ClearStyleRules()
+
+	// SetCellRenderer visualizes the values of columns (bars, sparklines,
+	// progress bars, heatmaps) using the context (value range, width) of the
+	// whole column. Setting a nil renderer removes the visualization.
+	SetCellRenderer(renderer CellRenderer, colnames ...string) error
+
 	// GetRow returns the nth row from the table or error if no such row exists
 	GetRow(nth int) (Row, error)
 
 	// Returns a row
 	GetRowByName(name string) (Row, error)
 
+	// SetIndex maintains hash indexes of the values of columns, which are
+	// used by FindRows instead of scanning all the rows
+	SetIndex(colnames ...string) error
+
+	// FindRows returns the body rows whose value of the column is (deeply)
+	// equal to the value
+	FindRows(column string, value interface{}) ([]Row, error)
+
+	// AddWindowColumn adds a column calculated by a window function (e.g.
+	// Rolling, CumSum, Lag, PctChange or Rank) from the values of another
+	// column in the current row order, optionally partitioned by the values of
+	// a partition column ("" = no partitions). Header and footer are skipped.
+	AddWindowColumn(name, column, partition string, window Window) error
+
+	// Describe returns a table of descriptive statistics (count, missing,
+	// mean, std, min, quartiles and max of numeric columns; count, missing,
+	// unique, top and freq of other columns) of the body rows of the columns
+	// (all the columns if none are provided)
+	Describe(colnames ...string) (Table, error)
+
+	// ValueCounts returns a frequency table (value, count, percent and
+	// cumulative percent, most frequent values first) of a column, optionally
+	// with a histogram bar
+	ValueCounts(column string, bar bool) (Table, error)
+
+	// Crosstab returns a contingency table of the values of two columns, with
+	// every cell aggregating the values of valueCol (rowCol if "") of the
+	// matching rows. Row totals are added as a column, column totals as the
+	// footer. The aggregate defaults to Count.
+	Crosstab(rowCol, colCol, valueCol string, aggregate Aggregate) (Table, error)
+
+	// Bin adds a categorical column assigning the body rows to bins (see
+	// EqualWidth, Quantiles, Edges and TimeBuckets) of a column's values. Bins
+	// are labeled by their ranges, unless labels are provided.
+	Bin(name, column string, bins Bins, labels ...string) error
+
+	// Histogram returns a table of the bins of a column's values with their
+	// counts and a bar chart
+	Histogram(column string, bins Bins) (Table, error)
+
 	// Transform a function to all the values in colnames
 	Transform(trans func(v interface{}) interface{}, colnames ...string) error
 
@@ -69,6 +166,40 @@ type Table interface {
 	// Rows without a unique name are treated as having a blank name, i.e. ""
 	FilterByRowNames(filter func(rowname string) bool, inplace, keepFooter bool) Table
 
+	// DropDuplicates removes body rows whose values of colnames (all the
+	// columns if none are provided) deeply equal the values of another row,
+	// keeping the first (KeepFirst), the last (KeepLast) or none (KeepNone) of
+	// them. If count is not "", a column of that name with the count of
+	// collapsed duplicates is added. Inplace and keepFooter are same as in
+	// Filter.
+	DropDuplicates(keep Keep, count string, inplace, keepFooter bool, colnames ...string) (Table, error)
+
+	// Distinct returns a new table of the distinct combinations of the values
+	// of colnames (all the columns if none are provided)
+	Distinct(colnames ...string) (Table, error)
+
+	// Append appends copies of the body rows of another table, aligning the
+	// columns by header names. Columns absent in the table are added, absent
+	// columns of the appended rows are missing. Conflicting row names fail
+	// (NameError), are suffixed (NameSuffix) or replace the existing rows
+	// (NameOverwrite). Body rows named after the header or the footer are
+	// conflicts as well, which are suffixed instead of replacing them. The
+	// other table's footer is dropped, its new titles and footnotes are added.
+	Append(other Table, conflict NameConflict) error
+
+	// Upsert updates the body row whose value of keyCol is (deeply) equal to
+	// the key among the values (inserted in the order of the columns) or
+	// appends a new row.
+	// Modifiers of the updated cells are kept (use Row.Change to reset them).
+	Upsert(keyCol string, values ...interface{}) (Row, error)
+
+	// MergeFrom upserts the body rows of another table matching them by the
+	// values of the key columns. Columns are aligned by their names. Values of
+	// matching rows are resolved by options.Resolve (e.g. PreferIncoming,
+	// PreferCurrent or Combine(Sum)). New rows with conflicting names (see
+	// SetStrict) are merged without a name and the first conflict is returned.
+	MergeFrom(other Table, options MergeOptions, keyCols ...string) error
+
 	// GetRowCount returns a number of rows
 	GetRowCount() int
 
@@ -88,6 +219,18 @@ type Table interface {
 	// to calculate cell widths.
 	Render(dst io.Writer, measureModified, modified, centered bool, template Template, columns ...string)
 
+	// RenderExpanded renders the table vertically (like psql's \x mode): every
+	// row is printed as a block of "column | value" lines preceded by a record
+	// separator. The footer, if any, is rendered as the last record.
+	RenderExpanded(dst io.Writer, measureModified, modified, centered bool, template Template, columns ...string)
+
+	// RenderAuto renders the table with Render, unless the table is wider than
+	// width, in which case RenderExpanded is used instead.
+	//
+	// Setting width to 0 uses the width of the terminal (os.Stdout). If it
+	// cannot be determined, then the table is always rendered with Render.
+	RenderAuto(dst io.Writer, width int, measureModified, modified, centered bool, template Template, columns ...string)
+
 	// Marshals the table to json including all meta information (row names,
 	// modified values, etc.)
 	MarshalToRichJSON(io.Writer) (int, error)
@@ -96,6 +239,53 @@ type Table interface {
 	// one object per row, i.e. [{col1: val1, col2: val2},{col1: val3, col2: val4}].
 	// It does not preserve modifiers, row names and so on.
 	MarshalToVanillaJSON(io.Writer) (int, error)
+
+	// MarshalToCSV marshals the header and the body rows (formatted, but not
+	// modified) as CSV. Header groups are merged into the column names,
+	// e.g. "Revenue / EUR".
+	MarshalToCSV(io.Writer) (int, error)
+
+	// MarshalToHTML marshals the table (formatted, but not modified) as an
+	// HTML table. Header groups are rendered as additional header rows and
+	// merged cells keep their colspan and rowspan.
+	MarshalToHTML(io.Writer) (int, error)
+
+	// AddHeaderGroup adds a label spanning the columns from first to last
+	// (both included) above the header, e.g. "Revenue" over "EUR" and "USD".
+	// Level 1 groups are rendered right above the header, level 2 groups
+	// above level 1 groups and so on. Groups of the same level cannot overlap.
+	AddHeaderGroup(label string, level int, first, last string) error
+
+	// ClearHeaderGroups removes all the header groups
+	ClearHeaderGroups()
+
+	// SetGrouping groups the body rows by the values of a column at render
+	// time (in the order of their first appearance). Every group gets a title
+	// and, if aggregates by column name are provided (e.g. Sum, Mean), a
+	// subtotal row. If the table has no footer, the aggregates of all the
+	// body rows are rendered as the footer (grand total).
+	SetGrouping(column string, aggregates map[string]Aggregate) error
+
+	// ClearGrouping renders the rows without groups
+	ClearGrouping()
+
+	// SetTreeDepth limits the count of rendered levels of tree tables
+	// (Row.AddChild). Depth 0 renders all the rows, depth 1 only the
+	// top-level rows, depth 2 also their children and so on.
+	SetTreeDepth(depth int) error
+
+	// SetTreeAggregates replaces the values of parent rows with the aggregates
+	// (by column name) of the values of their leaf descendants at render time
+	SetTreeAggregates(aggregates map[string]Aggregate) error
+
+	// FilterTree is same as Filter, only keeps the ancestors of the matching
+	// rows, so that the tree structure is preserved
+	FilterTree(filter func(values ...interface{}) bool, inplace, keepFooter bool, columns ...string) (Table, error)
+
+	// SetNestedTemplate sets the template of tables nested inside cells (a
+	// cell value may be a Table). Nil restores the default rounded-compact
+	// template.
+	SetNestedTemplate(template Template)
 }
 
 // Row represents a single table row.
@@ -120,6 +310,30 @@ type Row interface {
 	// This method is lazy, i.e. it only saves the reference to the modifier.
 	// The modification is done at render time if modified bool is set to true.
 	Modify(modifier func(interface{}) interface{}, colnames ...string) Row
+
+	// InsertStrict is same as Insert, only fails if no values or more values
+	// than the header has columns are provided
+	InsertStrict(values ...interface{}) error
+
+	// Set is same as Change, only fails if the column is not available
+	Set(colname string, value interface{}) error
+
+	// SetModifier is same as Modify, only fails (without modifying any cells)
+	// if some of the columns are not available
+	SetModifier(modifier func(interface{}) interface{}, colnames ...string) error
+
+	// Err returns the first error of the chainable methods (e.g. an unknown
+	// column passed to Change) recorded in strict mode (see Table.SetStrict)
+	Err() error
+
+	// AddChild adds a child row right after the last descendant of the row.
+	// Tree tables are rendered with tree glyphs (├─, └─, │) in the first column.
+	AddChild(name string) Row
+
+	// Span merges the cell with cols-1 cells to the right and rows-1 cells
+	// below. The values of the merged cells are hidden.
+	// Fails silently if column not available
+	Span(colname string, cols, rows int) Row
 }
 
 // Template handles
@@ -134,6 +348,23 @@ type Template interface {
 	// SetDisplayOptions sets some display options
 	SetDisplayOptions(center bool)
 
+	// SetSpans sets the merged cells of the next rendered row (header, regular
+	// row or footer). Empty spans render regular cells.
+	SetSpans(spans Spans)
+
+	// FlattenHeaders returns true if header groups (Table.AddHeaderGroup)
+	// should be merged into the column names, e.g. "Revenue / EUR", instead
+	// of being rendered as separate header tiers
+	FlattenHeaders() bool
+
+	// SetTheme sets the colors and text styles of the table regions (borders,
+	// header, footer, titles, footnotes, rows and columns). The theme is
+	// applied after the cell widths have been calculated.
+	SetTheme(theme Theme)
+
+	// Theme returns the current theme (with a resolved color profile)
+	Theme() Theme
+
 	// RenderHeader renders the header row
 	RenderHeader(mcells, pcells []string) []string
 
@@ -143,6 +374,21 @@ type Template interface {
 	// RenderFooter renders the footer row
 	RenderFooter(mcells, pcells []string) []string
 
+	// RenderGroupHeader renders the title of a row group (Table.SetGrouping)
+	// across all the columns
+	RenderGroupHeader(title string) []string
+
+	// RenderSubtotal renders the subtotal row of a row group
+	RenderSubtotal(mcells, pcells []string) []string
+
+	// RenderGroupSeparator renders the line between two row groups
+	RenderGroupSeparator() []string
+
+	// RenderRecord renders a single row in the expanded mode (Table.RenderExpanded).
+	//
+	// The widths of the key and value columns are set by SetColumnWidths.
+	RenderRecord(label string, mkeys, pkeys, mcells, pcells []string) []string
+
 	// RenderTitles renders table's titles
 	RenderTitles(titles []string) []string
 
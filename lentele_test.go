@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/fatih/color"
 	"io"
+	"reflect"
 	"testing"
 )
 
@@ -51,6 +52,46 @@ func badYear(vals ...interface{}) bool {
 	return false
 }
 
+// renderMySQL renders the modified cells of a table with the mysql template
+func renderMySQL(table Table, columns ...string) string {
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"), columns...)
+	return out.String()
+}
+
+// original returns the implementation of a table
+func original(t Table) *table {
+	return t.(*table)
+}
+
+// columnValues returns the values of a column of the body rows (nil if there
+// is no such column)
+func columnValues(t Table, column string) []interface{} {
+	impl := original(t)
+	impl.Lock()
+	defer impl.Unlock()
+
+	col := impl.getColnameIndex(column, false, true)
+	if col == -1 {
+		return nil
+	}
+
+	values := []interface{}{}
+	for _, r := range impl.bodyRows() {
+		values = append(values, cellValue(r, col))
+	}
+	return values
+}
+
+// checkValues compares the values of a column of the body rows with the
+// expected values
+func checkValues(t *testing.T, test string, table Table, column string, expected ...interface{}) {
+	values := columnValues(table, column)
+	if len(values) != len(expected) || (len(values) > 0 && !reflect.DeepEqual(values, expected)) {
+		t.Errorf("%s: expected the values %v of column '%s', got %v", test, expected, column, values)
+	}
+}
+
 func buildGDPTable(withHeader, header, footer bool) Table {
 
 	var table Table
@@ -117,14 +158,14 @@ func TestNew(t *testing.T) {
 	// Output
 	out := bytes.NewBuffer([]byte{})
 
-	table.Render(out, false, true, true, LoadTemplate("classic"))
-	table.Render(out, false, true, true, LoadTemplate("smooth"))
-	table.Render(out, false, true, true, LoadTemplate("modern"))
-	table.Render(out, false, true, true, LoadTemplate("classic"), "Year", "Inflation")
-	table.Render(out, true, true, true, LoadTemplate("classic"))
-	table.Render(out, false, false, true, LoadTemplate("classic"))
-	table.Render(out, false, false, false, LoadTemplate("classic"))
-	tableNooFoot.Render(out, false, true, true, LoadTemplate("classic"))
+	table.Render(out, false, true, true, MustLoadTemplate("classic"))
+	table.Render(out, false, true, true, MustLoadTemplate("smooth"))
+	table.Render(out, false, true, true, MustLoadTemplate("modern"))
+	table.Render(out, false, true, true, MustLoadTemplate("classic"), "Year", "Inflation")
+	table.Render(out, true, true, true, MustLoadTemplate("classic"))
+	table.Render(out, false, false, true, MustLoadTemplate("classic"))
+	table.Render(out, false, false, false, MustLoadTemplate("classic"))
+	tableNooFoot.Render(out, false, true, true, MustLoadTemplate("classic"))
 }
 
 func TestTransforms(t *testing.T) {
@@ -421,12 +462,17 @@ func TestMisc(t *testing.T) {
 	header := table.AddHeader([]string{"Year", "GDP growth", "Inflation"})
 	footer := table.AddFooter()
 
-	if header != table.AddRow("header") {
+	if header != table.AddHeader(nil) {
 		t.Errorf("TestMisc: repeatedly adding a header should return the same row")
 	}
 
-	if footer != table.AddRow("footer") {
-		t.Errorf("TestMisc: repeatedly adding a header footer return the same row")
+	if footer != table.AddFooter() {
+		t.Errorf("TestMisc: repeatedly adding a footer should return the same row")
+	}
+
+	// Reserved names
+	if header == table.AddRow("Header") || footer == table.AddRow("footer") {
+		t.Errorf("TestMisc: body rows should not be named after the header or the footer")
 	}
 
 	// Titles and footnotes
@@ -448,12 +494,12 @@ func TestMisc(t *testing.T) {
 
 	rownames := table.GetRowNames()
 	rowcount := table.GetRowCount()
-	lastRownames := rownames[rowcount-3:]
-	if len(rownames) != rowcount || lastRownames[0] != "typo" || lastRownames[1] != "header" || lastRownames[2] != "footer" {
+	lastRownames := rownames[rowcount-5:]
+	if len(rownames) != rowcount || lastRownames[0] != "typo" || lastRownames[1] != "header" || lastRownames[2] != "footer" || lastRownames[3] != "" || lastRownames[4] != "" {
 		t.Errorf("TestMisc: incorrect column names")
 	}
 
-	tmpl := LoadTemplate("classic")
+	tmpl := MustLoadTemplate("classic")
 	tmpl.PrintExample(bytes.NewBuffer([]byte{}))
 }
 
@@ -0,0 +1,242 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Formatter formats the raw values of a column at render time. It returns
+// false if it cannot format the value (e.g. the value is not numeric), in
+// which case the column's format (see Table.SetFormat) is used instead.
+type Formatter func(v interface{}) (string, bool)
+
+// Locale describes how numbers are written in a locale
+type Locale struct {
+	Decimal       string // Decimal separator
+	Thousands     string // Thousands separator
+	Grouping      []int  // Sizes of digit groups from the right, the last one repeats (nil = 3)
+	Currency      string // Currency symbol
+	CurrencyAfter bool   // The currency symbol follows the amount
+	CurrencySpace bool   // The currency symbol is separated by a (non-breaking) space
+	PercentSpace  bool   // The percent sign is separated by a (non-breaking) space
+}
+
+// Built-in locales
+var locales = map[string]*Locale{
+	"en-us": {Decimal: ".", Thousands: ",", Currency: "$"},
+	"en-gb": {Decimal: ".", Thousands: ",", Currency: "£"},
+	"en-in": {Decimal: ".", Thousands: ",", Grouping: []int{3, 2}, Currency: "₹"},
+	"de-de": {Decimal: ",", Thousands: ".", Currency: "€", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"de-ch": {Decimal: ".", Thousands: "’", Currency: "CHF", CurrencySpace: true},
+	"fr-fr": {Decimal: ",", Thousands: "\u00a0", Currency: "€", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"es-es": {Decimal: ",", Thousands: ".", Currency: "€", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"it-it": {Decimal: ",", Thousands: ".", Currency: "€", CurrencyAfter: true, CurrencySpace: true},
+	"nl-nl": {Decimal: ",", Thousands: ".", Currency: "€", CurrencySpace: true},
+	"pt-br": {Decimal: ",", Thousands: ".", Currency: "R$", CurrencySpace: true},
+	"pl-pl": {Decimal: ",", Thousands: "\u00a0", Currency: "zł", CurrencyAfter: true, CurrencySpace: true},
+	"lt-lt": {Decimal: ",", Thousands: "\u00a0", Currency: "€", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"sv-se": {Decimal: ",", Thousands: "\u00a0", Currency: "kr", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"ru-ru": {Decimal: ",", Thousands: "\u00a0", Currency: "₽", CurrencyAfter: true, CurrencySpace: true, PercentSpace: true},
+	"ja-jp": {Decimal: ".", Thousands: ",", Currency: "¥"},
+	"zh-cn": {Decimal: ".", Thousands: ",", Currency: "¥"},
+}
+
+// ListLocales returns the names of the built-in locales
+func ListLocales() []string {
+	names := []string{}
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadLocale returns a copy of a built-in locale, e.g. "en-US" or "de_DE"
+func LoadLocale(name string) (*Locale, error) {
+	locale, ok := locales[strings.Replace(strings.ToLower(name), "_", "-", -1)]
+	if !ok {
+		return nil, fmt.Errorf("LoadLocale: no such locale '%s'", name)
+	}
+
+	clone := *locale
+	return &clone, nil
+}
+
+// MustLoadLocale is same as LoadLocale, only panics if no such locale exists
+func MustLoadLocale(name string) *Locale {
+	locale, err := LoadLocale(name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return locale
+}
+
+// Number formats numeric values with thousands separators and a fixed count
+// of decimals
+func (l *Locale) Number(decimals int) Formatter {
+	return l.numeric(func(f float64) string {
+		return l.fixed(f, decimals)
+	})
+}
+
+// Significant formats numeric values with thousands separators, rounded to
+// the count of significant digits
+func (l *Locale) Significant(digits int) Formatter {
+	if digits < 1 {
+		digits = 1
+	}
+	return l.numeric(func(f float64) string {
+		if f == 0 {
+			return l.fixed(0, digits-1)
+		}
+		rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'e', digits-1, 64), 64)
+		decimals := digits - 1 - int(math.Floor(math.Log10(math.Abs(rounded))))
+		if decimals < 0 {
+			decimals = 0
+		}
+		return l.fixed(rounded, decimals)
+	})
+}
+
+// Percent formats fractions as percentages, i.e. 0.25 becomes 25%
+func (l *Locale) Percent(decimals int) Formatter {
+	return l.numeric(func(f float64) string {
+		if l.PercentSpace {
+			return l.fixed(f*100, decimals) + "\u00a0%"
+		}
+		return l.fixed(f*100, decimals) + "%"
+	})
+}
+
+// Money formats numeric values as amounts in the locale's currency. Negative
+// amounts are put in parentheses if accounting is set.
+func (l *Locale) Money(decimals int, accounting bool) Formatter {
+	return l.numeric(func(f float64) string {
+
+		amount := l.fixed(math.Abs(f), decimals)
+		switch {
+		case l.CurrencyAfter && l.CurrencySpace:
+			amount = amount + "\u00a0" + l.Currency
+		case l.CurrencyAfter:
+			amount = amount + l.Currency
+		case l.CurrencySpace:
+			amount = l.Currency + "\u00a0" + amount
+		default:
+			amount = l.Currency + amount
+		}
+
+		// Zero is never negative
+		if f >= 0 || l.fixed(math.Abs(f), decimals) == l.fixed(0, decimals) {
+			return amount
+		}
+		if accounting {
+			return "(" + amount + ")"
+		}
+		return "-" + amount
+	})
+}
+
+// Scientific formats numeric values in scientific notation, e.g. 1.23e+05
+func (l *Locale) Scientific(decimals int) Formatter {
+	if decimals < 0 {
+		decimals = 0
+	}
+	return l.numeric(func(f float64) string {
+		return strings.Replace(strconv.FormatFloat(f, 'e', decimals, 64), ".", l.Decimal, 1)
+	})
+}
+
+// numeric wraps a float formatter, so that non-numeric values are rejected
+func (l *Locale) numeric(format func(f float64) string) Formatter {
+	return func(v interface{}) (string, bool) {
+		f, ok := toFloat(v)
+		if !ok {
+			return "", false
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Sprintf("%v", f), true
+		}
+		return format(f), true
+	}
+}
+
+// fixed formats a float with a fixed count of decimals and digit groups
+func (l *Locale) fixed(f float64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	formatted := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	integer, fraction := formatted, ""
+	if dot := strings.Index(formatted, "."); dot != -1 {
+		integer, fraction = formatted[:dot], formatted[dot+1:]
+	}
+
+	// Digit groups
+	grouping := l.Grouping
+	if len(grouping) == 0 {
+		grouping = []int{3}
+	}
+	groups := []string{}
+	for k := 0; len(integer) > 0; k++ {
+		size := grouping[len(grouping)-1]
+		if k < len(grouping) {
+			size = grouping[k]
+		}
+		if size <= 0 || size >= len(integer) {
+			groups = append([]string{integer}, groups...)
+			break
+		}
+		groups = append([]string{integer[len(integer)-size:]}, groups...)
+		integer = integer[:len(integer)-size]
+	}
+
+	number := strings.Join(groups, l.Thousands)
+	if fraction != "" {
+		number += l.Decimal + fraction
+	}
+
+	// Negative zero is zero
+	if f < 0 && strings.Trim(formatted, "0.") != "" {
+		number = "-" + number
+	}
+
+	return number
+}
+
+// SetFormatter formats the values of columns with a formatter (see Locale)
+// at render time, leaving the raw values intact. Setting a nil formatter
+// removes the formatter. Fails without setting anything if any of the columns
+// is unknown.
+// NB: locks t
+func (t *table) SetFormatter(formatter Formatter, colnames ...string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(colnames) == 0 {
+		return fmt.Errorf("SetFormatter: provide at least one column name")
+	}
+
+	// Nothing is formatted if any of the columns is unknown
+	colIdx := []int{}
+	for _, colname := range colnames {
+		idx := t.getColnameIndex(colname, false, false)
+		if idx == -1 {
+			return fmt.Errorf("SetFormatter: no such column '%s'", colname)
+		}
+		colIdx = append(colIdx, idx)
+	}
+
+	for _, idx := range colIdx {
+		if formatter == nil {
+			delete(t.formatters, idx)
+		} else {
+			t.formatters[idx] = formatter
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLocaleFormatters(t *testing.T) {
+
+	us := MustLoadLocale("en-US")
+	de := MustLoadLocale("de_DE")
+	in := MustLoadLocale("en-IN")
+
+	cases := []struct {
+		formatter Formatter
+		value     interface{}
+		expected  string
+	}{
+		{us.Number(0), 983644505, "983,644,505"},
+		{us.Number(2), -1234.567, "-1,234.57"},
+		{us.Number(1), -0.01, "0.0"},
+		{de.Number(2), 1234567.891, "1.234.567,89"},
+		{in.Number(0), 12345678, "1,23,45,678"},
+		{us.Significant(3), 983644505, "984,000,000"},
+		{us.Significant(3), 0.0012345, "0.00123"},
+		{de.Significant(2), 1.26, "1,3"},
+		{us.Percent(1), 0.2534, "25.3%"},
+		{de.Percent(0), 0.5, "50\u00a0%"},
+		{us.Money(2, false), -1234.5, "-$1,234.50"},
+		{us.Money(2, true), -1234.5, "($1,234.50)"},
+		{de.Money(2, true), 1234.5, "1.234,50\u00a0€"},
+		{MustLoadLocale("pt-BR").Money(0, false), 1000, "R$\u00a01.000"},
+		{us.Scientific(2), 123456, "1.23e+05"},
+		{de.Scientific(1), 0.00042, "4,2e-04"},
+	}
+
+	for _, c := range cases {
+		if formatted, ok := c.formatter(c.value); !ok || formatted != c.expected {
+			t.Errorf("TestLocaleFormatters: expected '%s', got '%s' (%v)", c.expected, formatted, c.value)
+		}
+	}
+
+	if _, ok := us.Number(0)("text"); ok {
+		t.Errorf("TestLocaleFormatters: non-numeric values should be rejected")
+	}
+
+	if _, err := LoadLocale("xx-XX"); err == nil {
+		t.Errorf("TestLocaleFormatters: unknown locales should fail")
+	}
+}
+
+func TestSetFormatter(t *testing.T) {
+
+	table := New("Client", "Amount")
+	table.AddRow("").Insert("Acme", 43223.5)
+	table.AddRow("").Insert("Wayne", "n/a")
+
+	if err := table.SetFormatter(MustLoadLocale("en-US").Money(2, true), "Balance"); err == nil {
+		t.Errorf("TestSetFormatter: unknown columns should fail")
+	}
+	table.SetFormatter(MustLoadLocale("en-US").Money(2, true), "Amount")
+	if err := table.SetFormatter(MustLoadLocale("en-US").Percent(0), "Amount", "Balance"); err == nil {
+		t.Errorf("TestSetFormatter: any unknown column should fail")
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if rendered := out.String(); !strings.Contains(rendered, "| $43,223.50 |") || !strings.Contains(rendered, "|    n/a     |") {
+		t.Errorf("TestSetFormatter: unexpected output:\n%s", rendered)
+	}
+
+	// Raw values are left intact
+	jsoned := bytes.NewBuffer([]byte{})
+	table.MarshalToVanillaJSON(jsoned)
+	if !strings.Contains(jsoned.String(), `"Amount":43223.5`) {
+		t.Errorf("TestSetFormatter: raw values should be marshaled: %s", jsoned.String())
+	}
+}
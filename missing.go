@@ -0,0 +1,221 @@
+package lentele
+
+import (
+	"fmt"
+)
+
+// Missing marks a missing value. Unlike nil, which is a value, missing values
+// were never set. They are rendered with a placeholder (Table.SetPlaceholder),
+// skipped by aggregates and marshaled as JSON nulls or empty CSV fields. The
+// cells of rows shorter than the table are missing as well.
+var Missing = missingValue{}
+
+// missingValue is the type of Missing
+type missingValue struct{}
+
+// MarshalJSON marshals missing values as null
+func (missingValue) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// String returns an empty string
+func (missingValue) String() string {
+	return ""
+}
+
+// isMissing returns true if the value is missing
+func isMissing(v interface{}) bool {
+	_, ok := v.(missingValue)
+	return ok
+}
+
+// SetPlaceholder sets the placeholder of missing values of body rows for the
+// columns or, if no columns are provided, for the whole table. Missing values
+// are rendered empty by default.
+// NB: locks t
+func (t *table) SetPlaceholder(placeholder string, colnames ...string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(colnames) == 0 {
+		t.placeholders[-1] = placeholder
+		return nil
+	}
+
+	colIdx := t.getColIdx(colnames...)
+	if len(colIdx) == 0 {
+		return fmt.Errorf("SetPlaceholder: no such columns")
+	}
+
+	for _, idx := range colIdx {
+		t.placeholders[idx] = placeholder
+	}
+
+	return nil
+}
+
+// placeholder returns the placeholder of a column's missing values
+func (t *table) placeholder(col int) string {
+	if placeholder, ok := t.placeholders[col]; ok {
+		return placeholder
+	}
+	return t.placeholders[-1]
+}
+
+// FillMissing replaces the missing values of body rows. The fill is either a
+// value or an aggregate (e.g. Mean), which is given the values of the column
+// that are not missing. Fills all the columns if no columns are provided.
+// NB: locks t, then the rows
+func (t *table) FillMissing(fill interface{}, colnames ...string) error {
+	writes, err := t.fillMissing(fill, colnames...)
+	if err != nil {
+		return fmt.Errorf("FillMissing: %s", err.Error())
+	}
+
+	// The rows are locked before their table (same as in the row methods)
+	writeCells(writes)
+
+	return nil
+}
+
+// fillMissing returns the fills of the missing values (see FillMissing)
+// NB: locks t
+func (t *table) fillMissing(fill interface{}, colnames ...string) ([]cellWrite, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	colIdx, err := t.missingColumns(colnames...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregates calculate the fill of every column
+	var aggregate Aggregate
+	switch f := fill.(type) {
+	case Aggregate:
+		aggregate = f
+	case func(values []interface{}) interface{}:
+		aggregate = f
+	}
+
+	body := t.bodyRows()
+	writes := []cellWrite{}
+	for _, col := range colIdx {
+
+		value := fill
+		if aggregate != nil {
+			values := []interface{}{}
+			for _, row := range body {
+				if v := cellValue(row, col); !isMissing(v) {
+					values = append(values, v)
+				}
+			}
+			value = aggregate(values)
+		}
+
+		for _, row := range body {
+			if isMissing(cellValue(row, col)) {
+				writes = append(writes, cellWrite{row, col, value, true})
+			}
+		}
+	}
+
+	return writes, nil
+}
+
+// DropMissing removes the body rows having missing values in any of the
+// columns (all the columns if none are provided)
+// NB: locks t
+func (t *table) DropMissing(colnames ...string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	colIdx, err := t.missingColumns(colnames...)
+	if err != nil {
+		return fmt.Errorf("DropMissing: %s", err.Error())
+	}
+
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	selected := []int{}
+	for i, row := range t.Rows {
+		if row == header || row == footer {
+			continue
+		}
+		for _, col := range colIdx {
+			if col >= len(row.Cells) || isMissing(row.Cells[col].Value) {
+				selected = append(selected, i)
+				break
+			}
+		}
+	}
+
+	t.removeRows(selected)
+
+	return nil
+}
+
+// missingColumns returns the indices of the columns or, if none are
+// provided, of all the columns
+func (t *table) missingColumns(colnames ...string) ([]int, error) {
+
+	if len(colnames) > 0 {
+		colIdx := t.getColIdx(colnames...)
+		if len(colIdx) == 0 {
+			return nil, fmt.Errorf("no such columns")
+		}
+		return colIdx, nil
+	}
+
+	colIdx := []int{}
+	for k := 0; k < t.columnCount(); k++ {
+		colIdx = append(colIdx, k)
+	}
+	return colIdx, nil
+}
+
+// columnCount returns the count of columns, i.e. the length of the longest row
+func (t *table) columnCount() int {
+	count := 0
+	for _, row := range t.Rows {
+		if len(row.Cells) > count {
+			count = len(row.Cells)
+		}
+	}
+	return count
+}
+
+// bodyRows returns the rows except the header and the footer
+func (t *table) bodyRows() []*row {
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	rows := []*row{}
+	for _, row := range t.Rows {
+		if row != header && row != footer {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// markMissing flags the missing values for marshaling
+func (t *table) markMissing() {
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			cell.Missing = isMissing(cell.Value)
+		}
+	}
+}
+
+// restoreMissing restores the missing values of unmarshaled cells
+func (t *table) restoreMissing() {
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			if cell.Missing {
+				cell.Value = Missing
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlaceholders(t *testing.T) {
+
+	table := New("City", "GDP", "Inflation")
+	table.AddRow("").Insert("Vilnius", Missing, 0.4)
+	table.AddRow("").Insert("Kaunas")
+	table.AddRow("").Insert("Klaipeda", 3.0, nil)
+
+	if err := table.SetPlaceholder("?", "Population"); err == nil {
+		t.Errorf("TestPlaceholders: unknown columns should fail")
+	}
+
+	// Missing cells and cells of short rows are rendered as placeholders (of
+	// the column, if set), nil values are not missing
+	table.SetPlaceholder("n/a")
+	table.SetPlaceholder("?", "Inflation")
+	rendered := renderMySQL(table)
+	for _, line := range []string{
+		"| Vilnius  | n/a |    0.4    |",
+		"|  Kaunas  | n/a |     ?     |",
+		"| Klaipeda |  3  |   <nil>   |",
+	} {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("TestPlaceholders: line '%s' is missing:\n%s", line, rendered)
+		}
+	}
+
+	// Placeholders are not part of the data
+	checkValues(t, "TestPlaceholders", table, "GDP", Missing, Missing, 3.0)
+}
+
+func TestMissingExports(t *testing.T) {
+
+	table := New("City", "GDP")
+	table.AddRow("").Insert("Vilnius", Missing)
+	table.AddRow("").Insert("Kaunas")
+
+	// Aggregates skip missing values
+	if count := Count([]interface{}{1, nil, Missing}); count != 1 {
+		t.Errorf("TestMissingExports: expected a count of 1, got %v", count)
+	}
+
+	vanilla := bytes.NewBuffer([]byte{})
+	table.MarshalToVanillaJSON(vanilla)
+	if !strings.Contains(vanilla.String(), `{"City":"Kaunas","GDP":null}`) {
+		t.Errorf("TestMissingExports: missing values should be null: %s", vanilla.String())
+	}
+
+	csv := bytes.NewBuffer([]byte{})
+	table.MarshalToCSV(csv)
+	if !strings.Contains(csv.String(), "Vilnius,\nKaunas,\n") {
+		t.Errorf("TestMissingExports: missing values should be empty: %s", csv.String())
+	}
+
+	rich := bytes.NewBuffer([]byte{})
+	table.MarshalToRichJSON(rich)
+	restored, err := NewFromRichJSON(rich)
+	if err != nil {
+		t.Fatalf("TestMissingExports: could not unmarshal table: %s", err.Error())
+	}
+	checkValues(t, "TestMissingExports", restored, "GDP", Missing, Missing)
+}
+
+func TestFillMissing(t *testing.T) {
+
+	table := New("City", "GDP", "Inflation")
+	table.AddRow("").Insert("Vilnius", Missing, 0.4)
+	table.AddRow("").Insert("Kaunas")
+	table.AddRow("").Insert("Klaipeda", 3.0, nil)
+	table.AddRow("").Insert("Alytus", 1.0, 0.2)
+
+	if err := table.FillMissing(0, "Population"); err == nil {
+		t.Errorf("TestFillMissing: unknown columns should fail")
+	}
+
+	// Aggregates are given the values that are not missing
+	table.FillMissing(Mean, "GDP")
+	checkValues(t, "TestFillMissing", table, "GDP", 2.0, 2.0, 3.0, 1.0)
+	checkValues(t, "TestFillMissing", table, "Inflation", 0.4, Missing, nil, 0.2)
+
+	// Short rows are padded, nil values are kept
+	table.FillMissing(0.0)
+	checkValues(t, "TestFillMissing", table, "Inflation", 0.4, 0.0, nil, 0.2)
+}
+
+func TestFillMissingConcurrently(t *testing.T) {
+
+	table := New("a", "b")
+	changed := table.AddRow("").Insert(1, Missing)
+
+	// Filling (table, then row) must not deadlock changing (row, then table)
+	done := make(chan bool)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	go func() {
+		for time.Now().Before(deadline) {
+			table.FillMissing(0, "b")
+		}
+		done <- true
+	}()
+	go func() {
+		for time.Now().Before(deadline) {
+			changed.Change("b", Missing)
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("TestFillMissingConcurrently: deadlock")
+		}
+	}
+}
+
+func TestDropMissing(t *testing.T) {
+
+	table := New("City", "GDP", "Inflation")
+	table.AddRow("").Insert("Vilnius", Missing, 0.4)
+	table.AddRow("").Insert("Kaunas")
+	table.AddRow("").Insert("Klaipeda", 3.0, nil)
+
+	// Nil values are not missing
+	table.DropMissing("Inflation")
+	checkValues(t, "TestDropMissing", table, "City", "Vilnius", "Klaipeda")
+
+	table.DropMissing()
+	checkValues(t, "TestDropMissing", table, "City", "Klaipeda")
+}
+
+func TestVanillaMissing(t *testing.T) {
+
+	table, err := NewFromVanillaJSON(bytes.NewBufferString(`[{"x":1},{"y":2},{"x":3}]`), "NA")
+	if err != nil {
+		t.Fatalf("TestVanillaMissing: could not unmarshal table: %s", err.Error())
+	}
+
+	vanilla := bytes.NewBuffer([]byte{})
+	table.MarshalToVanillaJSON(vanilla)
+	if strings.Contains(vanilla.String(), "NA") {
+		t.Errorf("TestVanillaMissing: placeholders should not be part of the data: %s", vanilla.String())
+	}
+	if rendered := renderMySQL(table); !strings.Contains(rendered, "NA") {
+		t.Errorf("TestVanillaMissing: placeholders should be rendered:\n%s", rendered)
+	}
+}
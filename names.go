@@ -0,0 +1,161 @@
+package lentele
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// isReserved returns true for the names of the header and the footer
+func isReserved(name string) bool {
+	switch strings.ToLower(name) {
+	case "header", "footer":
+		return true
+	}
+	return false
+}
+
+// uniqueName returns the name of a new body row, which is blank if the name
+// is reserved or, in strict mode, already used (the error is recorded by the
+// row in strict mode)
+func (t *table) uniqueName(name string) (string, error) {
+	switch {
+	case name == "":
+	case isReserved(name):
+		return "", fmt.Errorf("row name '%s' is reserved", name)
+	case t.Strict && len(t.rowsByName(name)) > 0:
+		return "", fmt.Errorf("row name '%s' is already used", name)
+	}
+	return name, nil
+}
+
+// AddNamedRow adds a new body row with a unique name. Fails if the name is
+// blank, reserved ("header" and "footer") or already used.
+// NB: locks t
+func (t *table) AddNamedRow(name string) (Row, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	name = strings.ToLower(name)
+	switch {
+	case name == "":
+		return nil, fmt.Errorf("AddNamedRow: the name cannot be blank")
+	case isReserved(name):
+		return nil, fmt.Errorf("AddNamedRow: the name '%s' is reserved", name)
+	case len(t.rowsByName(name)) > 0:
+		return nil, fmt.Errorf("AddNamedRow: row '%s' already exists", name)
+	}
+
+	return t.addRow(name, false), nil
+}
+
+// SetStrict enables (or disables) the strict mode. In strict mode row names
+// are unique: rows added with a used name are not named (same as rows added
+// with a reserved name in any mode). Fails if the table already has duplicate
+// row names.
+// NB: locks t
+func (t *table) SetStrict(strict bool) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if strict {
+		if name := t.duplicateName(); name != "" {
+			return fmt.Errorf("SetStrict: duplicate row name '%s'", name)
+		}
+	}
+	t.Strict = strict
+
+	return nil
+}
+
+// duplicateName returns the first duplicate (or reserved) row name of the body
+// rows
+func (t *table) duplicateName() string {
+	seen := map[string]bool{}
+	for i, name := range t.RowNames {
+		if t.Rows[i] == t.headAndFoot["header"] || t.Rows[i] == t.headAndFoot["footer"] || name == "" {
+			continue
+		}
+		if seen[name] || isReserved(name) {
+			return name
+		}
+		seen[name] = true
+	}
+	return ""
+}
+
+// NewFromRichJSONStrict is same as NewFromRichJSON, only resolves duplicate
+// row names (including body rows named "header" or "footer") by failing
+// (NameError), suffixing them (NameSuffix) or replacing the earlier rows
+// (NameOverwrite), and returns a table in strict mode (see Table.SetStrict)
+func NewFromRichJSONStrict(source io.Reader, conflict NameConflict) (Table, error) {
+
+	imported, err := NewFromRichJSON(source)
+	if err != nil {
+		return nil, fmt.Errorf("NewFromRichJSONStrict: %s", err.Error())
+	}
+
+	t := imported.(*table)
+	if err := t.resolveNames(conflict); err != nil {
+		return nil, fmt.Errorf("NewFromRichJSONStrict: %s", err.Error())
+	}
+	t.Strict = true
+
+	return t, nil
+}
+
+// resolveNames makes the names of the body rows unique
+func (t *table) resolveNames(conflict NameConflict) error {
+
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	rows := []*row{}
+	rowNames := []string{}
+	used := map[string]int{} // Positions of the named rows
+	taken := map[string]bool{}
+	for _, name := range t.RowNames {
+		taken[name] = true
+	}
+
+	for i, r := range t.Rows {
+		name := t.RowNames[i]
+
+		_, ok := used[name]
+		if r == header || r == footer || name == "" || (!ok && !isReserved(name)) {
+			if name != "" && r != header && r != footer {
+				used[name] = len(rows)
+			}
+			rows = append(rows, r)
+			rowNames = append(rowNames, name)
+			continue
+		}
+
+		switch {
+		case conflict == NameError:
+			return fmt.Errorf("duplicate row name '%s'", name)
+		case conflict == NameOverwrite && ok:
+			rows[used[name]] = r
+			continue
+		case conflict == NameSuffix || conflict == NameOverwrite:
+			suffixed := name
+			for n := 2; taken[suffixed]; n++ {
+				suffixed = fmt.Sprintf("%s_%d", name, n)
+			}
+			taken[suffixed] = true
+			name = suffixed
+		default:
+			return fmt.Errorf("unknown name conflict treatment %d", conflict)
+		}
+
+		used[name] = len(rows)
+		rows = append(rows, r)
+		rowNames = append(rowNames, name)
+	}
+
+	t.Rows = rows
+	t.RowNames = rowNames
+	t.restructure()
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAddNamedRow(t *testing.T) {
+
+	table := New("Host", "Load")
+	if _, err := table.AddNamedRow("web1"); err != nil {
+		t.Fatalf("TestAddNamedRow: could not add row: %s", err.Error())
+	}
+
+	for _, name := range []string{"", "WEB1", "Header", "footer"} {
+		if _, err := table.AddNamedRow(name); err == nil {
+			t.Errorf("TestAddNamedRow: adding a row named '%s' should fail", name)
+		}
+	}
+
+	if table.GetRowCount() != 2 {
+		t.Errorf("TestAddNamedRow: expected 2 rows, got %d", table.GetRowCount())
+	}
+}
+
+func TestReservedNames(t *testing.T) {
+
+	table := New("Host", "Load")
+	web1 := table.AddRow("Header").Insert("web1", 1)
+	web1.AddChild("footer").Insert("web2", 2)
+	table.AddFooter().Insert("Total", 3)
+
+	if names := strings.Join(table.GetRowNames(), ","); names != "header,,,footer" {
+		t.Errorf("TestReservedNames: unexpected row names %s", names)
+	}
+	if header, _ := table.GetRowByName("header"); header == web1 {
+		t.Errorf("TestReservedNames: body rows should not be named after the header")
+	}
+	if web1.Err() != nil {
+		t.Errorf("TestReservedNames: errors should only be recorded in strict mode")
+	}
+}
+
+func TestStrict(t *testing.T) {
+
+	table := New("Host", "Load")
+	table.AddRow("web1").Insert("web1", 1)
+	table.AddRow("web1").Insert("web1", 2)
+	if err := table.SetStrict(true); err == nil {
+		t.Errorf("TestStrict: duplicate row names should fail")
+	}
+	table.RemoveRows(2)
+
+	if err := table.SetStrict(true); err != nil {
+		t.Fatalf("TestStrict: could not set strict mode: %s", err.Error())
+	}
+
+	// Duplicate and reserved names are not used
+	header, _ := table.GetRowByName("header")
+	if table.AddRow("Header") == header {
+		t.Errorf("TestStrict: body rows should not be named after the header")
+	}
+	table.AddRow("web1").Insert("web1", 3)
+	web1, _ := table.GetRow(1)
+	web1.AddChild("web1")
+	table.AddFooter().Insert("Total", 4)
+
+	names := strings.Join(table.GetRowNames(), ",")
+	if names != "header,web1,,,,footer" {
+		t.Errorf("TestStrict: unexpected row names %s", names)
+	}
+}
+
+func TestNewFromRichJSONStrict(t *testing.T) {
+
+	table := New("Host", "Load")
+	table.AddRow("web1").Insert("web1", 1)
+	table.AddRow("web2").Insert("web2", 2)
+	table.AddRow("WEB1").Insert("web1", 3)
+	table.AddRow("web1_2").Insert("web1_2", 4)
+
+	marshaled := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToRichJSON(marshaled); err != nil {
+		t.Fatalf("TestNewFromRichJSONStrict: could not marshal the table: %s", err.Error())
+	}
+
+	if _, err := NewFromRichJSONStrict(bytes.NewReader(marshaled.Bytes()), NameError); err == nil {
+		t.Errorf("TestNewFromRichJSONStrict: duplicate row names should fail")
+	}
+
+	tests := []struct {
+		conflict NameConflict
+		names    string
+		load     interface{}
+	}{
+		{NameSuffix, "header,web1,web2,web1_3,web1_2", 1.0},
+		{NameOverwrite, "header,web1,web2,web1_2", 3.0},
+	}
+
+	for _, test := range tests {
+		imported, err := NewFromRichJSONStrict(bytes.NewReader(marshaled.Bytes()), test.conflict)
+		if err != nil {
+			t.Fatalf("TestNewFromRichJSONStrict: could not import the table: %s", err.Error())
+		}
+
+		if names := strings.Join(imported.GetRowNames(), ","); names != test.names {
+			t.Errorf("TestNewFromRichJSONStrict: expected row names %s, got %s", test.names, names)
+		}
+		if web1, _ := imported.GetRowByName("web1"); cellValue(web1.(*row), 1) != test.load {
+			t.Errorf("TestNewFromRichJSONStrict: expected load %v, got %v", test.load, cellValue(web1.(*row), 1))
+		}
+
+		// Imported tables are strict
+		imported.AddRow("web2")
+		if names := imported.GetRowNames(); names[len(names)-1] != "" {
+			t.Errorf("TestNewFromRichJSONStrict: imported tables should be strict")
+		}
+	}
+}
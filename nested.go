@@ -0,0 +1,155 @@
+package lentele
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// SetNestedTemplate sets the template of tables nested inside cells. Nested
+// tables are rendered with the rounded-compact template by default (nil).
+// NB: locks t
+func (t *table) SetNestedTemplate(template Template) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.nested = template
+}
+
+// nestedTemplate returns a fresh template for nested tables
+func (t *table) nestedTemplate() Template {
+	switch tmpl := t.nested.(type) {
+	case nil:
+		return tmplRoundedCompact()
+	case *template:
+		return tmpl.clone()
+	default:
+		return tmpl
+	}
+}
+
+// renderNested renders a table nested inside a cell as a block of lines of
+// equal width. Returns the normal and the modified rendering. Tables
+// containing themselves (directly or through other nested tables) are
+// rendered as "<cycle>" instead.
+func (t *table) renderNested(nested Table, measureModified, modified bool) (string, string) {
+
+	inner, ok := nested.(*table)
+	if ok && (inner == t || t.enclosing[inner]) {
+		return "<cycle>", "<cycle>"
+	}
+
+	render := func(modified bool) string {
+		buf := bytes.NewBuffer([]byte{})
+		if ok {
+			inner.renderEnclosed(t, buf, measureModified, modified, t.nestedTemplate())
+		} else {
+			nested.Render(buf, measureModified, modified, false, t.nestedTemplate())
+		}
+		block := trimBlankLines(buf.String())
+		return padLines(block, blockWidth(stripANSI(block)))
+	}
+
+	valueNorm := render(false)
+	if !modified {
+		return valueNorm, valueNorm
+	}
+
+	return valueNorm, render(true)
+}
+
+// renderEnclosed renders t nested inside a cell of the (locked) enclosing
+// table, remembering the tables rendered around t
+// NB: locks t
+func (t *table) renderEnclosed(enclosing *table, dst io.Writer, measureModified, modified bool, template Template) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.enclosing = map[*table]bool{enclosing: true}
+	for outer := range enclosing.enclosing {
+		t.enclosing[outer] = true
+	}
+	defer func() { t.enclosing = nil }()
+
+	t.render(dst, measureModified, modified, false, template)
+}
+
+// trimBlankLines removes the leading and trailing blank lines
+func trimBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for len(lines) > 0 && strings.TrimSpace(stripANSI(lines[0])) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(stripANSI(lines[len(lines)-1])) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// vanillaValue converts nested tables to their vanilla JSON representation.
+// Tables enclosing the value (directly or through other nested tables) are
+// converted to "<cycle>" instead.
+func vanillaValue(value interface{}, enclosing map[*table]bool) interface{} {
+	nested, ok := value.(Table)
+	if !ok {
+		return value
+	}
+
+	inner, ok := nested.(*table)
+	if !ok {
+		buf := bytes.NewBuffer([]byte{})
+		if _, err := nested.MarshalToVanillaJSON(buf); err != nil {
+			return nil
+		}
+		return json.RawMessage(buf.Bytes())
+	}
+
+	if enclosing[inner] {
+		return "<cycle>"
+	}
+
+	return inner.vanillaEnclosed(enclosing)
+}
+
+// vanillaEnclosed converts t nested inside a cell of the (locked) enclosing
+// tables to its vanilla JSON representation
+// NB: locks t
+func (t *table) vanillaEnclosed(enclosing map[*table]bool) []map[string]interface{} {
+	t.Lock()
+	defer t.Unlock()
+
+	inner := map[*table]bool{t: true}
+	for outer := range enclosing {
+		inner[outer] = true
+	}
+
+	return t.vanillaRows(inner)
+}
+
+// restoreNested restores the tables nested inside cells of an unmarshaled
+// table, i.e. objects having rows and row names
+func (t *table) restoreNested() {
+	for _, row := range t.Rows {
+		for _, cell := range row.Cells {
+			object, ok := cell.Value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := object["rows"]; !ok {
+				continue
+			}
+			if _, ok := object["rownames"]; !ok {
+				continue
+			}
+
+			jsoned, err := json.Marshal(object)
+			if err != nil {
+				continue
+			}
+			if nested, err := NewFromRichJSON(bytes.NewBuffer(jsoned)); err == nil {
+				cell.Value = nested
+			}
+		}
+	}
+}
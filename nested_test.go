@@ -0,0 +1,124 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nestedOrders returns a table of orders with nested order lines
+func nestedOrders() Table {
+	lines := New("Item", "Qty")
+	lines.AddRow("").Insert("apple", 3)
+	lines.AddRow("").Insert("pear", 12)
+
+	table := New("Order", "Lines", "Status")
+	table.AddRow("").Insert(1001, lines, "shipped")
+	table.AddRow("").Insert(1002, "-", "open")
+
+	return table
+}
+
+func TestNested(t *testing.T) {
+
+	table := nestedOrders()
+
+	// Nested tables are rendered as blocks (rounded-compact by default), the
+	// other cells of the row are centered vertically
+	rendered := renderMySQL(table)
+	for _, line := range []string{
+		"|       | ╭───────┬─────╮ |         |",
+		"| 1001  | ├───────┼─────┤ | shipped |",
+		"|       | │ pear  │ 12  │ |         |",
+	} {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("TestNested: line '%s' is missing:\n%s", line, rendered)
+		}
+	}
+
+	// Custom nested template
+	table.SetNestedTemplate(MustLoadTemplate("ascii"))
+	if rendered := renderMySQL(table); strings.Contains(rendered, "╭") || !strings.Contains(rendered, "| +=======+=====+ |") {
+		t.Errorf("TestNested: nested template was not used:\n%s", rendered)
+	}
+}
+
+func TestNestedJSON(t *testing.T) {
+
+	table := nestedOrders()
+
+	jsoned := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToVanillaJSON(jsoned); err != nil {
+		t.Fatalf("TestNestedJSON: could not marshal table: %s", err.Error())
+	}
+	vanilla := `[{"Lines":[{"Item":"apple","Qty":3},{"Item":"pear","Qty":12}],"Order":1001,"Status":"shipped"},{"Lines":"-","Order":1002,"Status":"open"}]`
+	if jsoned.String() != vanilla {
+		t.Errorf("TestNestedJSON: unexpected vanilla JSON: %s", jsoned.String())
+	}
+
+	jsoned.Reset()
+	if _, err := table.MarshalToRichJSON(jsoned); err != nil {
+		t.Fatalf("TestNestedJSON: could not marshal table: %s", err.Error())
+	}
+	restored, err := NewFromRichJSON(jsoned)
+	if err != nil {
+		t.Fatalf("TestNestedJSON: could not unmarshal table: %s", err.Error())
+	}
+	if renderMySQL(restored) != renderMySQL(table) {
+		t.Errorf("TestNestedJSON: nested tables should survive a round trip:\n%s", renderMySQL(restored))
+	}
+}
+
+func TestNestedCycles(t *testing.T) {
+
+	outer := New("Name", "Inner")
+	inner := New("Name", "Outer")
+	outer.AddRow("").Insert("outer", inner)
+	inner.AddRow("").Insert("inner", outer)
+
+	// Tables nested inside each other are rendered once
+	done := make(chan string)
+	go func() { done <- renderMySQL(outer) }()
+
+	select {
+	case rendered := <-done:
+		if strings.Count(rendered, "<cycle>") != 1 || !strings.Contains(rendered, "inner") {
+			t.Errorf("TestNestedCycles: unexpected output:\n%s", rendered)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("TestNestedCycles: rendering does not stop")
+	}
+
+	// Vanilla JSON marks the cycle, rich JSON fails
+	vanilla := make(chan string)
+	go func() {
+		buf := bytes.NewBuffer([]byte{})
+		outer.MarshalToVanillaJSON(buf)
+		vanilla <- buf.String()
+	}()
+
+	select {
+	case jsoned := <-vanilla:
+		if expected := `[{"Inner":[{"Name":"inner","Outer":"\u003ccycle\u003e"}],"Name":"outer"}]`; jsoned != expected {
+			t.Errorf("TestNestedCycles: expected vanilla JSON %s, got %s", expected, jsoned)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("TestNestedCycles: vanilla marshaling does not stop")
+	}
+
+	rich := make(chan error)
+	go func() {
+		_, err := outer.MarshalToRichJSON(bytes.NewBuffer([]byte{}))
+		rich <- err
+	}()
+
+	select {
+	case err := <-rich:
+		if err == nil {
+			t.Errorf("TestNestedCycles: rich marshaling of cycles should fail")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("TestNestedCycles: rich marshaling does not stop")
+	}
+}
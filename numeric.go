@@ -0,0 +1,25 @@
+package lentele
+
+import (
+	"reflect"
+)
+
+// toFloat converts numeric values (ints, uints and floats) to float64
+func toFloat(v interface{}) (float64, bool) {
+
+	if v == nil {
+		return 0, false
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,294 @@
+package lentele
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// registry contains all the named templates
+var registry = &templateRegistry{
+	Mutex: &sync.Mutex{},
+	templates: map[string]func() Template{
+		"classic":         func() Template { return tmplClassic() },
+		"smooth":          func() Template { return tmplSmooth() },
+		"modern":          func() Template { return tmplModern() },
+		"ascii":           func() Template { return tmplASCII() },
+		"double":          func() Template { return tmplDouble() },
+		"heavy":           func() Template { return tmplHeavy() },
+		"rounded-compact": func() Template { return tmplRoundedCompact() },
+		"dotted":          func() Template { return tmplDotted() },
+		"plain":           func() Template { return tmplPlain() },
+		"minimal":         func() Template { return tmplMinimal() },
+		"psql":            func() Template { return tmplPSQL() },
+		"mysql":           func() Template { return tmplMySQL() },
+		"rst-grid":        func() Template { return tmplRSTGrid() },
+		"rst-simple":      func() Template { return tmplRSTSimple() },
+		"org":             func() Template { return tmplOrg() },
+		"markdown":        func() Template { return tmplMarkdown() },
+	},
+}
+
+// templateRegistry maps template names to template constructors
+type templateRegistry struct {
+	*sync.Mutex
+	templates map[string]func() Template
+}
+
+// TemplateSpec is a serializable definition of a table template.
+//
+// Corners (H1, H3, C1, C3, F1, F3) must have four elements: the left corner,
+// the horizontal line, the junction and the right corner. Walls (H2, C2, F2)
+// must have three elements: the left, the inner and the right wall. See the
+// documentation of the template struct for a drawing.
+//
+// A spec can be unmarshaled from JSON or YAML (see ParseTemplateSpec), e.g.:
+//  h1: ["╔", "═", "╦", "╗"]
+//  h2: ["║", "║", "║"]
+//  ...
+//  skipC1: true
+type TemplateSpec struct {
+	SkipH1      bool `json:"skipH1,omitempty" yaml:"skipH1"`
+	SkipH3      bool `json:"skipH3,omitempty" yaml:"skipH3"`
+	SkipC1      bool `json:"skipC1,omitempty" yaml:"skipC1"`
+	SkipC3      bool `json:"skipC3,omitempty" yaml:"skipC3"`
+	SkipF1      bool `json:"skipF1,omitempty" yaml:"skipF1"`
+	SkipF3      bool `json:"skipF3,omitempty" yaml:"skipF3"`
+	SkipFirstC1 bool `json:"skipFirstC1,omitempty" yaml:"skipFirstC1"`
+	SkipLastC3  bool `json:"skipLastC3,omitempty" yaml:"skipLastC3"`
+
+	H1 []string `json:"h1" yaml:"h1"`
+	H2 []string `json:"h2" yaml:"h2"`
+	H3 []string `json:"h3" yaml:"h3"`
+	C1 []string `json:"c1" yaml:"c1"`
+	C2 []string `json:"c2" yaml:"c2"`
+	C3 []string `json:"c3" yaml:"c3"`
+	F1 []string `json:"f1" yaml:"f1"`
+	F2 []string `json:"f2" yaml:"f2"`
+	F3 []string `json:"f3" yaml:"f3"`
+
+	// HR is used to underline the footnotes (defaults to "─")
+	HR string `json:"hr,omitempty" yaml:"hr"`
+
+	// HJ joins the walls of two header tiers (defaults to h3[2])
+	HJ string `json:"hj,omitempty" yaml:"hj"`
+
+	// GS is the optional corner-like separator of row groups
+	GS []string `json:"gs,omitempty" yaml:"gs"`
+
+	// FlattenHeaders merges header groups into the column names, e.g.
+	// "Revenue / EUR", instead of rendering them as separate header tiers
+	FlattenHeaders bool `json:"flattenHeaders,omitempty" yaml:"flattenHeaders"`
+}
+
+// ParseTemplateSpec reads a template spec from a JSON or YAML source and
+// validates it
+func ParseTemplateSpec(source io.Reader) (*TemplateSpec, error) {
+
+	// Read marshaled input
+	marshaled, err := readMarshaled(source)
+	if err != nil {
+		return nil, fmt.Errorf("ParseTemplateSpec: could not read from source: %s", err.Error())
+	}
+
+	// YAML is a superset of JSON, so both are handled by the same unmarshaler
+	spec := &TemplateSpec{}
+	if err := yaml.Unmarshal(marshaled, spec); err != nil {
+		return nil, fmt.Errorf("ParseTemplateSpec: could not unmarshal spec: %s", err.Error())
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("ParseTemplateSpec: %s", err.Error())
+	}
+
+	return spec, nil
+}
+
+// Validate checks whether all the corners and walls are well defined
+func (s *TemplateSpec) Validate() error {
+
+	corners := []struct {
+		name  string
+		parts []string
+	}{
+		{"h1", s.H1}, {"h3", s.H3}, {"c1", s.C1}, {"c3", s.C3}, {"f1", s.F1}, {"f3", s.F3},
+	}
+
+	walls := []struct {
+		name  string
+		parts []string
+	}{
+		{"h2", s.H2}, {"c2", s.C2}, {"f2", s.F2},
+	}
+
+	// Group separators are optional
+	if len(s.GS) > 0 {
+		corners = append(corners, struct {
+			name  string
+			parts []string
+		}{"gs", s.GS})
+	}
+
+	for _, corner := range corners {
+		if len(corner.parts) != 4 {
+			return fmt.Errorf("Validate: %s must have 4 elements, got %d", corner.name, len(corner.parts))
+		}
+		if utf8.RuneCountInString(corner.parts[1]) != 1 {
+			return fmt.Errorf("Validate: %s must have a single character horizontal line", corner.name)
+		}
+		if err := validateParts(corner.name, corner.parts); err != nil {
+			return err
+		}
+	}
+
+	for _, wall := range walls {
+		if len(wall.parts) != 3 {
+			return fmt.Errorf("Validate: %s must have 3 elements, got %d", wall.name, len(wall.parts))
+		}
+		if err := validateParts(wall.name, wall.parts); err != nil {
+			return err
+		}
+	}
+
+	if utf8.RuneCountInString(s.HR) > 1 {
+		return fmt.Errorf("Validate: hr must be a single character")
+	}
+
+	if utf8.RuneCountInString(s.HJ) > 1 {
+		return fmt.Errorf("Validate: hj must be a single character")
+	}
+
+	return nil
+}
+
+// Template creates a new template from the spec
+func (s *TemplateSpec) Template() (Template, error) {
+
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("Template: invalid spec: %s", err.Error())
+	}
+
+	tmpl := &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipH1:           s.SkipH1,
+		SkipH3:           s.SkipH3,
+		SkipC1:           s.SkipC1,
+		SkipC3:           s.SkipC3,
+		SkipF1:           s.SkipF1,
+		SkipF3:           s.SkipF3,
+		SkipFirstC1:      s.SkipFirstC1,
+		SkipLastC3:       s.SkipLastC3,
+		HR:               s.HR,
+		HJ:               s.HJ,
+		FlatHeaders:      s.FlattenHeaders,
+	}
+
+	copy(tmpl.H1[:], s.H1)
+	copy(tmpl.H2[:], s.H2)
+	copy(tmpl.H3[:], s.H3)
+	copy(tmpl.C1[:], s.C1)
+	copy(tmpl.C2[:], s.C2)
+	copy(tmpl.C3[:], s.C3)
+	copy(tmpl.F1[:], s.F1)
+	copy(tmpl.F2[:], s.F2)
+	copy(tmpl.F3[:], s.F3)
+	copy(tmpl.GS[:], s.GS)
+
+	if tmpl.HR == "" {
+		tmpl.HR = "─"
+	}
+
+	return tmpl, nil
+}
+
+// RegisterTemplate registers a template under the given (case insensitive)
+// name, so that it can be loaded with LoadTemplate. Registering a template
+// under an existing name replaces the previous template.
+//
+// Templates created by this package (LoadTemplate, TemplateSpec.Template) are
+// copied on each LoadTemplate call, so that a registered template can be used
+// by several tables at once. Other implementations are returned as they are.
+func RegisterTemplate(name string, tmpl Template) error {
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("RegisterTemplate: template name cannot be empty")
+	}
+	if tmpl == nil {
+		return fmt.Errorf("RegisterTemplate: template cannot be nil")
+	}
+
+	constructor := func() Template { return tmpl }
+	if tmplStruct, ok := tmpl.(*template); ok {
+		proto := tmplStruct.clone()
+		constructor = func() Template { return proto.clone() }
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.templates[name] = constructor
+
+	return nil
+}
+
+// LoadTemplate returns the named template or an error if no such template
+// has been registered
+func LoadTemplate(name string) (Template, error) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	constructor, ok := registry.templates[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("LoadTemplate: unknown template '%s' (available: %s)", name, strings.Join(listTemplates(), ", "))
+	}
+
+	return constructor(), nil
+}
+
+// MustLoadTemplate is like LoadTemplate, only panics if no such template exists
+func MustLoadTemplate(name string) Template {
+	tmpl, err := LoadTemplate(name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return tmpl
+}
+
+// ListTemplates returns the sorted names of all the registered templates
+func ListTemplates() []string {
+	registry.Lock()
+	defer registry.Unlock()
+
+	return listTemplates()
+}
+
+// listTemplates returns the sorted names of all the registered templates
+// NB: registry must be locked
+func listTemplates() []string {
+	names := make([]string, 0, len(registry.templates))
+	for name := range registry.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// validateParts makes sure that corner and wall elements are at most one
+// character wide
+func validateParts(name string, parts []string) error {
+	for i, part := range parts {
+		if utf8.RuneCountInString(part) > 1 {
+			return fmt.Errorf("Validate: %s[%d] must be at most one character wide", name, i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const yamlSpec = `
+skipC1: true
+skipLastC3: true
+h1: ["+", "=", "+", "+"]
+h2: ["|", "|", "|"]
+h3: ["+", "=", "+", "+"]
+c1: ["+", "-", "+", "+"]
+c2: ["|", "|", "|"]
+c3: ["+", "-", "+", "+"]
+f1: ["+", "=", "+", "+"]
+f2: [" ", " ", " "]
+f3: [" ", " ", " ", " "]
+hr: "-"
+`
+
+const jsonSpec = `{
+	"skipC1": true,
+	"h1": ["+", "=", "+", "+"], "h2": ["|", "|", "|"], "h3": ["+", "=", "+", "+"],
+	"c1": ["+", "-", "+", "+"], "c2": ["|", "|", "|"], "c3": ["+", "-", "+", "+"],
+	"f1": ["+", "=", "+", "+"], "f2": [" ", " ", " "], "f3": [" ", " ", " ", " "]
+}`
+
+func TestParseTemplateSpec(t *testing.T) {
+
+	tests := []struct {
+		source string
+		isErr  bool
+	}{
+		{yamlSpec, false},
+		{jsonSpec, false},
+		{strings.Replace(yamlSpec, `h1: ["+", "=", "+", "+"]`, `h1: ["+", "=", "+"]`, 1), true},
+		{strings.Replace(yamlSpec, `c2: ["|", "|", "|"]`, `c2: ["|", "|"]`, 1), true},
+		{strings.Replace(yamlSpec, `c3: ["+", "-", "+", "+"]`, `c3: ["+", "--", "+", "+"]`, 1), true},
+		{strings.Replace(jsonSpec, `"f3"`, `"f4"`, 1), true},
+		{yamlSpec + `gs: ["+", "~", "+", "+"]`, false},
+		{yamlSpec + `gs: ["+", "~", "+"]`, true},
+		{yamlSpec + `gs: ["+", "~~", "+", "+"]`, true},
+		{"[Not YAML", true},
+	}
+
+	for i, test := range tests {
+		spec, err := ParseTemplateSpec(bytes.NewBufferString(test.source))
+		if (err != nil) != test.isErr {
+			t.Errorf("TestParseTemplateSpec: test %d failed: unexpected error state: %v", i+1, err)
+			continue
+		}
+		if test.isErr {
+			continue
+		}
+
+		tmpl, err := spec.Template()
+		if err != nil {
+			t.Errorf("TestParseTemplateSpec: test %d failed: %s", i+1, err.Error())
+			continue
+		}
+
+		out := bytes.NewBuffer([]byte{})
+		buildGDPTable(true, true, true).Render(out, false, true, false, tmpl)
+		if !strings.Contains(out.String(), "+========+") {
+			t.Errorf("TestParseTemplateSpec: test %d failed: template was not used", i+1)
+		}
+	}
+}
+
+func TestTemplateRegistry(t *testing.T) {
+
+	spec, err := ParseTemplateSpec(bytes.NewBufferString(yamlSpec))
+	if err != nil {
+		t.Fatalf("TestTemplateRegistry: could not parse spec: %s", err.Error())
+	}
+	tmpl, _ := spec.Template()
+
+	if err := RegisterTemplate("", tmpl); err == nil {
+		t.Errorf("TestTemplateRegistry: registering a template without a name should fail")
+	}
+
+	if err := RegisterTemplate("plus", nil); err == nil {
+		t.Errorf("TestTemplateRegistry: registering a nil template should fail")
+	}
+
+	if err := RegisterTemplate("Plus", tmpl); err != nil {
+		t.Errorf("TestTemplateRegistry: could not register template: %s", err.Error())
+	}
+
+	loaded, err := LoadTemplate("PLUS")
+	if err != nil {
+		t.Errorf("TestTemplateRegistry: could not load registered template: %s", err.Error())
+	} else if loaded == tmpl {
+		t.Errorf("TestTemplateRegistry: registered templates should be copied")
+	}
+
+	if _, err := LoadTemplate("clasic"); err == nil {
+		t.Errorf("TestTemplateRegistry: loading an unknown template should fail")
+	}
+
+	names := strings.Join(ListTemplates(), ",")
+	for _, name := range []string{"classic", "modern", "plus", "smooth"} {
+		if !strings.Contains(names, name) {
+			t.Errorf("TestTemplateRegistry: template '%s' is not listed", name)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("TestTemplateRegistry: MustLoadTemplate should panic on unknown templates")
+		}
+	}()
+	MustLoadTemplate("clasic")
+}
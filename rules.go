@@ -0,0 +1,298 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of style rules
+const (
+	RuleCustom   = "custom"
+	RuleRange    = "range"
+	RuleTop      = "top"
+	RuleBottom   = "bottom"
+	RuleNegative = "negative"
+	RuleRegex    = "regex"
+)
+
+// StyleRule styles the cells of a column (or whole rows) whose raw values
+// satisfy a condition. Rules are evaluated at render time and only affect the
+// modified view of a table (i.e. Render(..., modified=true, ...)). The style
+// is applied on top of the cell modifiers and does not affect cell widths.
+//
+// All rules except the custom ones (PredicateRule) are exported by
+// MarshalToRichJSON and restored by NewFromRichJSON.
+type StyleRule struct {
+	Column   string   `json:"column"`
+	Kind     string   `json:"kind"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	N        int      `json:"n,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Style    Style    `json:"style"`
+	WholeRow bool     `json:"row,omitempty"`
+
+	predicate func(v interface{}) bool
+	regex     *regexp.Regexp
+}
+
+// PredicateRule styles the cells whose values satisfy the predicate
+func PredicateRule(column string, predicate func(v interface{}) bool, style Style) *StyleRule {
+	return &StyleRule{Column: column, Kind: RuleCustom, Style: style, predicate: predicate}
+}
+
+// RangeRule styles the numeric cells within [min, max]. Use math.Inf for open
+// ranges.
+func RangeRule(column string, min, max float64, style Style) *StyleRule {
+	rule := &StyleRule{Column: column, Kind: RuleRange, Style: style}
+	if !math.IsInf(min, 0) {
+		rule.Min = &min
+	}
+	if !math.IsInf(max, 0) {
+		rule.Max = &max
+	}
+	return rule
+}
+
+// TopRule styles the cells containing the n largest numeric values of the
+// column (ties included)
+func TopRule(column string, n int, style Style) *StyleRule {
+	return &StyleRule{Column: column, Kind: RuleTop, N: n, Style: style}
+}
+
+// BottomRule styles the cells containing the n smallest numeric values of the
+// column (ties included)
+func BottomRule(column string, n int, style Style) *StyleRule {
+	return &StyleRule{Column: column, Kind: RuleBottom, N: n, Style: style}
+}
+
+// NegativeRule styles the cells containing negative numbers
+func NegativeRule(column string, style Style) *StyleRule {
+	return &StyleRule{Column: column, Kind: RuleNegative, Style: style}
+}
+
+// RegexRule styles the cells whose values (formatted with %v) match the
+// regular expression
+func RegexRule(column, pattern string, style Style) (*StyleRule, error) {
+	rule := &StyleRule{Column: column, Kind: RuleRegex, Pattern: pattern, Style: style}
+	if err := rule.compile(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Row makes the rule style whole rows instead of single cells
+func (r *StyleRule) Row() *StyleRule {
+	r.WholeRow = true
+	return r
+}
+
+// compile prepares the rule for evaluation
+func (r *StyleRule) compile() error {
+
+	switch r.Kind {
+	case RuleCustom:
+		if r.predicate == nil {
+			return fmt.Errorf("compile: custom rule for column '%s' has no predicate", r.Column)
+		}
+
+	case RuleRegex:
+		regex, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("compile: invalid pattern '%s': %s", r.Pattern, err.Error())
+		}
+		r.regex = regex
+
+	case RuleTop, RuleBottom:
+		if r.N <= 0 {
+			return fmt.Errorf("compile: %s rule requires a positive N", r.Kind)
+		}
+
+	case RuleRange, RuleNegative:
+
+	default:
+		return fmt.Errorf("compile: unknown rule kind '%s'", r.Kind)
+	}
+
+	return nil
+}
+
+// matcher returns a function evaluating the rule against the column's values
+func (r *StyleRule) matcher(values []interface{}) func(v interface{}) bool {
+
+	switch r.Kind {
+	case RuleCustom:
+		return r.predicate
+
+	case RuleRegex:
+		return func(v interface{}) bool {
+			return r.regex.MatchString(fmt.Sprintf("%v", v))
+		}
+
+	case RuleNegative:
+		return func(v interface{}) bool {
+			f, ok := toFloat(v)
+			return ok && f < 0
+		}
+
+	case RuleRange:
+		return func(v interface{}) bool {
+			f, ok := toFloat(v)
+			if !ok {
+				return false
+			}
+			return (r.Min == nil || f >= *r.Min) && (r.Max == nil || f <= *r.Max)
+		}
+
+	default:
+
+		// Top/bottom: find the threshold
+		numbers := []float64{}
+		for _, v := range values {
+			if f, ok := toFloat(v); ok {
+				numbers = append(numbers, f)
+			}
+		}
+		if len(numbers) == 0 {
+			return func(interface{}) bool { return false }
+		}
+
+		sort.Float64s(numbers)
+		n := r.N
+		if n > len(numbers) {
+			n = len(numbers)
+		}
+
+		if r.Kind == RuleTop {
+			threshold := numbers[len(numbers)-n]
+			return func(v interface{}) bool {
+				f, ok := toFloat(v)
+				return ok && f >= threshold
+			}
+		}
+
+		threshold := numbers[n-1]
+		return func(v interface{}) bool {
+			f, ok := toFloat(v)
+			return ok && f <= threshold
+		}
+	}
+}
+
+// AddStyleRule styles the cells of a column whose values satisfy the predicate
+// NB: locks t
+func (t *table) AddStyleRule(column string, predicate func(v interface{}) bool, style Style) error {
+	if predicate == nil {
+		return fmt.Errorf("AddStyleRule: predicate cannot be nil")
+	}
+	return t.AddStyleRules(PredicateRule(column, predicate, style))
+}
+
+// AddStyleRules adds conditional formatting rules to the table
+// NB: locks t
+func (t *table) AddStyleRules(rules ...*StyleRule) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(rules) == 0 {
+		return fmt.Errorf("AddStyleRules: provide at least one rule")
+	}
+
+	for _, rule := range rules {
+		if rule == nil {
+			return fmt.Errorf("AddStyleRules: rule cannot be nil")
+		}
+		if len(t.getColIdx(rule.Column)) == 0 {
+			return fmt.Errorf("AddStyleRules: no such column '%s'", rule.Column)
+		}
+		if err := rule.compile(); err != nil {
+			return fmt.Errorf("AddStyleRules: %s", err.Error())
+		}
+	}
+
+	t.Rules = append(t.Rules, rules...)
+
+	return nil
+}
+
+// ClearStyleRules removes all the style rules
+// NB: locks t
+func (t *table) ClearStyleRules() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.Rules = nil
+}
+
+// evalRules evaluates the style rules against the raw values of body rows and
+// returns the styles of cells (by row and column index) and of whole rows
+// (by row index)
+func (t *table) evalRules() (map[int]map[int]Style, map[int]Style) {
+
+	cellStyles := map[int]map[int]Style{}
+	rowStyles := map[int]Style{}
+
+	if len(t.Rules) == 0 {
+		return cellStyles, rowStyles
+	}
+
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	for _, rule := range t.Rules {
+
+		colIdx := t.getColIdx(rule.Column)
+		if len(colIdx) == 0 {
+			continue
+		}
+		col := colIdx[0]
+
+		// Gather column values
+		values := map[int]interface{}{}
+		list := []interface{}{}
+		for i, row := range t.Rows {
+			if row == header || row == footer || col >= len(row.Cells) {
+				continue
+			}
+			values[i] = row.Cells[col].Value
+			list = append(list, row.Cells[col].Value)
+		}
+
+		// Evaluate
+		match := rule.matcher(list)
+		for i, value := range values {
+			if !match(value) {
+				continue
+			}
+			if rule.WholeRow {
+				rowStyles[i] = rowStyles[i].Merge(rule.Style)
+				continue
+			}
+			if _, ok := cellStyles[i]; !ok {
+				cellStyles[i] = map[int]Style{}
+			}
+			cellStyles[i][col] = cellStyles[i][col].Merge(rule.Style)
+		}
+	}
+
+	return cellStyles, rowStyles
+}
+
+// restoreRules compiles unmarshaled rules and drops the ones that cannot be
+// restored (custom rules)
+func restoreRules(rules []*StyleRule) []*StyleRule {
+	restored := []*StyleRule{}
+	for _, rule := range rules {
+		if rule == nil || strings.TrimSpace(rule.Column) == "" {
+			continue
+		}
+		if err := rule.compile(); err != nil {
+			continue
+		}
+		restored = append(restored, rule)
+	}
+	return restored
+}
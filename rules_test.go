@@ -0,0 +1,119 @@
+package lentele
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func renderThemed(table Table) string {
+	tmpl := MustLoadTemplate("classic")
+	tmpl.SetTheme(Theme{Profile: ProfileANSI})
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, tmpl)
+
+	return out.String()
+}
+
+func TestStyleRules(t *testing.T) {
+
+	red := Style{Fg: Red}
+	green := Style{Fg: Green}
+
+	regex, err := RegexRule("Year", "^201[0-2]$", Style{Bold: true})
+	if err != nil {
+		t.Fatalf("TestStyleRules: could not create regex rule: %s", err.Error())
+	}
+
+	tests := []struct {
+		rule     *StyleRule
+		expected []string
+		count    int
+	}{
+		{NegativeRule("GDP growth", red), []string{"\033[31m-14.81416331\033[0m"}, 2},
+		{RangeRule("Inflation", 10, math.Inf(1), green), []string{"\033[32m10.9274114655\033[0m"}, 1},
+		{TopRule("GDP growth", 2, green), []string{"\033[32m11.086954387\033[0m", "\033[32m10.538564772\033[0m"}, 2},
+		{BottomRule("Inflation", 1, red), []string{"\033[31m-1.1457530021\033[0m"}, 1},
+		{regex, []string{"\033[1m2010\033[0m", "\033[1m2012\033[0m"}, 3},
+		{NegativeRule("Inflation", red).Row(), []string{"\033[31m2003\033[0m", "\033[31m2015\033[0m"}, 7}, // 2015 contains a two-line slice
+	}
+
+	for i, test := range tests {
+		table := buildGDPTable(true, true, true)
+		if err := table.AddStyleRules(test.rule); err != nil {
+			t.Errorf("TestStyleRules: test %d failed: %s", i+1, err.Error())
+			continue
+		}
+
+		rendered := renderThemed(table)
+		for _, expected := range test.expected {
+			if !strings.Contains(rendered, expected) {
+				t.Errorf("TestStyleRules: test %d failed: %q not found", i+1, expected)
+			}
+		}
+		if count := strings.Count(rendered, "\033[0m"); count != test.count {
+			t.Errorf("TestStyleRules: test %d failed: expected %d styled cells, got %d", i+1, test.count, count)
+		}
+	}
+}
+
+func TestStyleRulesComposition(t *testing.T) {
+
+	table := buildGDPTable(true, true, true)
+	row, _ := table.GetRow(4)
+	row.Modify(round, "GDP growth")
+
+	table.AddStyleRule("GDP growth", func(v interface{}) bool {
+		f, ok := v.(float64)
+		return ok && f < 0
+	}, Style{Fg: Red})
+
+	if rendered := renderThemed(table); !strings.Contains(rendered, "\033[31m-1.13\033[0m") {
+		t.Errorf("TestStyleRulesComposition: rules should style modified values")
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, false, false, MustLoadTemplate("classic"))
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("TestStyleRulesComposition: rules should not style unmodified views")
+	}
+
+	if err := table.AddStyleRules(NegativeRule("No such column", Style{})); err == nil {
+		t.Errorf("TestStyleRulesComposition: rules for unknown columns should fail")
+	}
+	if _, err := RegexRule("Year", "[", Style{}); err == nil {
+		t.Errorf("TestStyleRulesComposition: invalid patterns should fail")
+	}
+
+	table.ClearStyleRules()
+	if rendered := renderThemed(table); strings.Contains(rendered, "\033[") {
+		t.Errorf("TestStyleRulesComposition: cleared rules should not style cells")
+	}
+}
+
+func TestStyleRulesJSON(t *testing.T) {
+
+	table := buildGDPTable(true, true, true)
+	table.AddStyleRules(NegativeRule("GDP growth", Style{Fg: Red}), TopRule("Inflation", 1, Style{Bold: true}).Row())
+	table.AddStyleRule("Year", func(v interface{}) bool { return true }, Style{Underline: true})
+
+	jsoned := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToRichJSON(jsoned); err != nil {
+		t.Fatalf("TestStyleRulesJSON: could not marshal table: %s", err.Error())
+	}
+
+	restored, err := NewFromRichJSON(jsoned)
+	if err != nil {
+		t.Fatalf("TestStyleRulesJSON: could not unmarshal table: %s", err.Error())
+	}
+
+	rendered := renderThemed(restored)
+	if !strings.Contains(rendered, "\033[31m-14.81416331\033[0m") || !strings.Contains(rendered, "\033[1m2008\033[0m") {
+		t.Errorf("TestStyleRulesJSON: serializable rules should survive a round trip")
+	}
+	if strings.Contains(rendered, "\033[4m") {
+		t.Errorf("TestStyleRulesJSON: custom rules cannot be restored")
+	}
+}
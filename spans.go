@@ -0,0 +1,166 @@
+package lentele
+
+import (
+	"fmt"
+)
+
+// Spans describes the merged cells of a rendered row. All the slices are
+// indexed by the position of the rendered column (starting at 0). Missing
+// entries describe regular cells.
+type Spans struct {
+	Cols []int  // Count of columns merged into the cell (0 = merged into a cell to the left)
+	Up   []bool // The cell continues a cell of the previous row (row span)
+	Down []bool // The cell continues in the next row (row span)
+
+	// Header tiers (see Table.AddHeaderGroup)
+	Above []int // Column spans of the header tier rendered above, nil if none
+	Below bool  // Another header tier is rendered below
+
+	// Neighbouring rows (the borders join the walls of both rows)
+	Prev []int // Column spans of the row rendered above, nil if none
+	Next []int // Column spans of the row rendered below, nil if none
+}
+
+// cols returns the column span of the i-th cell
+func (s Spans) cols(i int) int {
+	if i < len(s.Cols) {
+		return s.Cols[i]
+	}
+	return 1
+}
+
+// up returns true if the i-th cell continues a cell of the previous row
+func (s Spans) up(i int) bool {
+	return i < len(s.Up) && s.Up[i]
+}
+
+// down returns true if the i-th cell continues in the next row
+func (s Spans) down(i int) bool {
+	return i < len(s.Down) && s.Down[i]
+}
+
+// columns returns the column spans of all the columns (regular cells if
+// there are no spans)
+func (s Spans) columns(count int) []int {
+	columns := make([]int, count)
+	for i := range columns {
+		columns[i] = s.cols(i)
+	}
+	return columns
+}
+
+// opened returns whether the cells of the columns are open (up or down),
+// including the columns merged into the open cells
+func (s Spans) opened(open func(i int) bool, columns int) []bool {
+	opened := make([]bool, columns)
+	owner := -1
+	for i := range opened {
+		if s.cols(i) != 0 {
+			owner = i
+		}
+		opened[i] = owner != -1 && open(owner)
+	}
+	return opened
+}
+
+// mergedSpans returns the spans of a row merging all the columns into a single
+// cell (e.g. the titles of row groups)
+func mergedSpans(widths []int) Spans {
+	spans := Spans{Cols: make([]int, len(widths))}
+	if first := firstColumn(widths); first != -1 {
+		spans.Cols[first] = len(widths) - first
+	}
+	return spans
+}
+
+// linkSpans sets the column spans of the neighbouring rows (in the order of
+// rendering)
+func linkSpans(rows ...*Spans) {
+	for n := 1; n < len(rows); n++ {
+		rows[n-1].Next = rows[n].Cols
+		rows[n].Prev = rows[n-1].Cols
+	}
+}
+
+// add appends the span info of the next cell
+func (s *Spans) add(cols int, up, down bool) {
+	s.Cols = append(s.Cols, cols)
+	s.Up = append(s.Up, up)
+	s.Down = append(s.Down, down)
+}
+
+// rowCover tracks a cell spanning into the following rows
+type rowCover struct {
+	rows int // Count of rows still to be covered
+	cols int // Column span of the covering cell at this position
+}
+
+// spannedCell is a cell spanning several columns
+type spannedCell struct {
+	pos, span, length int
+}
+
+// Span merges the cell of the column with the cells to the right (cols) and
+// below (rows). The values of the merged cells are hidden. Row spans are only
+// honored in body rows and never extend into the header or footer.
+// Fails silently if column not available (records an error in strict mode).
+func (r *row) Span(colname string, cols, rows int) Row {
+	r.Lock()
+	defer r.Unlock()
+
+	index := r.columnIndex(colname)
+	if index == -1 || index >= len(r.Cells) {
+		r.record(fmt.Errorf("Span: no such cell '%s'", colname))
+		return r
+	}
+
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	// Change the spans
+	rcell := r.Cells[index]
+	rcell.Lock()
+	rcell.ColSpan, rcell.RowSpan = 0, 0
+	if cols > 1 {
+		rcell.ColSpan = cols
+	}
+	if rows > 1 {
+		rcell.RowSpan = rows
+	}
+	rcell.Unlock()
+
+	return r
+}
+
+// distributeSpans widens the columns of cells spanning several columns, so
+// that the spanned cells fit. The extra width is distributed evenly.
+func distributeSpans(widths []int, wide []spannedCell) {
+	for _, w := range wide {
+		available := -3
+		for k := w.pos; k < w.pos+w.span; k++ {
+			available += widths[k] + 3
+		}
+		for k := 0; k < w.length-available; k++ {
+			widths[w.pos+k%w.span]++
+		}
+	}
+}
+
+// closeRowSpans makes sure that cells only continue into rows that continue
+// them (e.g. row spans reaching the last body row)
+func closeRowSpans(spans []Spans, body []int) {
+	for n, i := range body {
+		for j := range spans[i].Down {
+			if !spans[i].Down[j] {
+				continue
+			}
+			if n == len(body)-1 || !spans[body[n+1]].up(j) {
+				spans[i].Down[j] = false
+			}
+		}
+	}
+}
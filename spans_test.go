@@ -0,0 +1,107 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// buildSpanTable builds a small table with merged cells
+func buildSpanTable() Table {
+	table := New("Region", "Jan", "Feb", "Mar")
+	table.AddRow("").Insert("Quarterly sales", "", "", "").Span("Region", 4, 1)
+	table.AddRow("").Insert("North", 1, 2, 3).Span("Region", 1, 2)
+	table.AddRow("").Insert("", 4, 5, 6)
+	table.AddRow("").Insert("South", "Closed for renovation", "", "").Span("Jan", 3, 1)
+	table.AddFooter().Insert("Total", 5, 7, 9)
+
+	return table
+}
+
+func TestSpans(t *testing.T) {
+
+	out := bytes.NewBuffer([]byte{})
+	buildSpanTable().Render(out, false, true, false, MustLoadTemplate("mysql"))
+
+	expected := []string{
+		"+--------+-------+-------+-------+",
+		"| Region |  Jan  |  Feb  |  Mar  |",
+		"+--------+-------+-------+-------+",
+		"|        Quarterly sales         |",
+		"| North  |   1   |   2   |   3   |",
+		"|        |   4   |   5   |   6   |",
+		"| South  | Closed for renovation |",
+		"+--------+-------+-------+-------+",
+		"| Total  |   5   |   7   |   9   |",
+	}
+
+	if rendered := out.String(); !strings.Contains(rendered, strings.Join(expected, "\n")) {
+		t.Errorf("TestSpans: unexpected output:\n%s", rendered)
+	}
+
+	// Borders between merged cells
+	out.Reset()
+	buildSpanTable().Render(out, false, true, false, MustLoadTemplate("classic"))
+	rendered := out.String()
+
+	for _, line := range []string{
+		"╠════════╩═══════╩═══════╩═══════╣",
+		"╟────────┬───────┬───────┬───────╢",
+		"║        ├───────┼───────┼───────╢",
+		"╟────────┼───────┴───────┴───────╢",
+		"╚════════╧═══════════════════════╝",
+	} {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("TestSpans: line '%s' is missing:\n%s", line, rendered)
+		}
+	}
+
+	// Spans over hidden columns are shortened
+	out.Reset()
+	buildSpanTable().Render(out, false, true, false, MustLoadTemplate("mysql"), "Region", "Jan", "Mar")
+	if !strings.Contains(out.String(), "| South  | Closed for renovation |") {
+		t.Errorf("TestSpans: spans should only merge rendered columns:\n%s", out.String())
+	}
+}
+
+func TestSpansGolden(t *testing.T) {
+
+	// Cells spanning columns and rows next to each other
+	table := New("Region", "Store", "Jan", "Feb", "Mar")
+	table.AddRow("").Insert("North", "Riga", 1, 2, 3).Span("Region", 1, 3)
+	table.AddRow("").Insert("", "Vilnius", "Closed", "", "").Span("Jan", 2, 2)
+	table.AddRow("").Insert("", "Tallinn", "", "", 4).Span("Store", 1, 2)
+	table.AddRow("").Insert("South", "", 5, 6, 7)
+	table.AddRow("").Insert("Total", "", 6, 8, 14).Span("Region", 2, 1)
+	table.AddFooter().Insert("Checked", "", "", "yes", "").Span("Feb", 2, 1)
+
+	for _, name := range []string{"classic", "smooth", "heavy", "ascii", "rst-grid"} {
+		out := bytes.NewBuffer([]byte{})
+		table.Render(out, false, true, false, MustLoadTemplate(name))
+		checkGolden(t, "TestSpansGolden", "spans-"+name, out.Bytes())
+	}
+}
+
+func TestSpansJSON(t *testing.T) {
+
+	jsoned := bytes.NewBuffer([]byte{})
+	if _, err := buildSpanTable().MarshalToRichJSON(jsoned); err != nil {
+		t.Fatalf("TestSpansJSON: could not marshal table: %s", err.Error())
+	}
+	if !strings.Contains(jsoned.String(), `"colspan":4`) || !strings.Contains(jsoned.String(), `"rowspan":2`) {
+		t.Errorf("TestSpansJSON: spans should be marshaled")
+	}
+
+	restored, err := NewFromRichJSON(jsoned)
+	if err != nil {
+		t.Fatalf("TestSpansJSON: could not unmarshal table: %s", err.Error())
+	}
+
+	expected, got := bytes.NewBuffer([]byte{}), bytes.NewBuffer([]byte{})
+	buildSpanTable().Render(expected, false, true, false, MustLoadTemplate("classic"))
+	restored.Render(got, false, true, false, MustLoadTemplate("classic"))
+
+	if expected.String() != got.String() {
+		t.Errorf("TestSpansJSON: spans should survive a round trip:\n%s", got.String())
+	}
+}
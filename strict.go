@@ -0,0 +1,184 @@
+package lentele
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Set changes the value of a row's cell (same as Change), only fails if the
+// column is not available
+func (r *row) Set(colname string, value interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if err := r.set(colname, value); err != nil {
+		return fmt.Errorf("Set: %s", err.Error())
+	}
+
+	return nil
+}
+
+// SetModifier sets the modifier of the cells (same as Modify), only fails
+// without modifying any cell if some of the columns are not available
+func (r *row) SetModifier(modifier func(interface{}) interface{}, colnames ...string) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(colnames) == 0 {
+		return fmt.Errorf("SetModifier: provide at least one column name")
+	}
+
+	if err := r.modify(modifier, false, colnames...); err != nil {
+		return fmt.Errorf("SetModifier: %s", err.Error())
+	}
+
+	return nil
+}
+
+// InsertStrict inserts values into the row (same as Insert), only fails
+// without inserting anything if no values are provided or if the row would
+// have more cells than the header has columns
+func (r *row) InsertStrict(values ...interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if len(values) == 0 {
+		return fmt.Errorf("InsertStrict: no values provided")
+	}
+
+	if limit := r.columnLimit(); limit != -1 && len(r.Cells)+len(values) > limit {
+		return fmt.Errorf("InsertStrict: too many values (%d cells, %d columns)", len(r.Cells)+len(values), limit)
+	}
+
+	r.insert(values...)
+
+	return nil
+}
+
+// Err returns the first error of the row's chainable methods recorded in
+// strict mode (see Table.SetStrict)
+func (r *row) Err() error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.err
+}
+
+// record records the first error of the chainable methods in strict mode.
+// The row has to be locked by the caller.
+// NB: locks the parent table
+func (r *row) record(err error) {
+	if err == nil || r.err != nil || r.tref == nil {
+		return
+	}
+
+	r.tref.Lock()
+	strict := r.tref.Strict
+	r.tref.Unlock()
+
+	if strict {
+		r.err = err
+	}
+}
+
+// columnIndex returns the position of a column (-1 if there is no such
+// column). The row has to be locked by the caller.
+// NB: locks the parent table (and the header)
+func (r *row) columnIndex(colname string) int {
+
+	colname = strings.ToLower(colname)
+
+	// Header and footer rows
+	header := r.tref.headAndFoot["header"]
+	footer := r.tref.headAndFoot["footer"]
+
+	switch r {
+	case header, footer:
+		return r.tref.getColnameIndex(colname, true, false)
+	default:
+		return r.tref.getColnameIndex(colname, true, true)
+	}
+}
+
+// columnLimit returns the count of the header's columns (-1 if the row is the
+// header or there is no header). The row has to be locked by the caller.
+// NB: locks the parent table and the header
+func (r *row) columnLimit() int {
+	if r.tref == nil {
+		return -1
+	}
+
+	r.tref.Lock()
+	header, ok := r.tref.headAndFoot["header"]
+	r.tref.Unlock()
+
+	if !ok || header == r {
+		return -1
+	}
+
+	header.Lock()
+	defer header.Unlock()
+
+	return len(header.Cells)
+}
+
+// set changes the value of a cell, padding short rows with missing values.
+// The row has to be locked by the caller.
+func (r *row) set(colname string, value interface{}) error {
+
+	index := r.columnIndex(colname)
+	if index == -1 {
+		return fmt.Errorf("no such column '%s'", colname)
+	}
+
+	for len(r.Cells) <= index {
+		r.Cells = append(r.Cells, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+	}
+
+	rcell := r.Cells[index]
+	rcell.Lock()
+	rcell.Value = value
+	rcell.modFunc = func(v interface{}) interface{} { return v }
+	rcell.Unlock()
+	r.changed()
+
+	return nil
+}
+
+// modify sets the modifier of the cells, padding short rows with missing
+// values. Unknown columns are skipped if partial is set, otherwise nothing is
+// modified. The row has to be locked by the caller.
+func (r *row) modify(modifier func(interface{}) interface{}, partial bool, colnames ...string) error {
+
+	indices := []int{}
+	unknown := []string{}
+	for _, colname := range colnames {
+		if index := r.columnIndex(colname); index != -1 {
+			indices = append(indices, index)
+		} else {
+			unknown = append(unknown, colname)
+		}
+	}
+
+	if len(unknown) > 0 && !partial {
+		return fmt.Errorf("no such columns '%s'", strings.Join(unknown, "', '"))
+	}
+
+	for _, index := range indices {
+		for len(r.Cells) <= index {
+			r.Cells = append(r.Cells, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+		}
+
+		rcell := r.Cells[index]
+		rcell.Lock()
+		rcell.modFunc = modifier
+		rcell.Unlock()
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("no such columns '%s'", strings.Join(unknown, "', '"))
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package lentele
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRowErrors(t *testing.T) {
+
+	table := New("Host", "Load")
+	web1 := table.AddRow("web1")
+	if err := web1.InsertStrict(); err == nil {
+		t.Errorf("TestRowErrors: inserting no values should fail")
+	}
+	if err := web1.InsertStrict("web1", 1, "extra"); err == nil {
+		t.Errorf("TestRowErrors: inserting too many values should fail")
+	}
+	if err := web1.InsertStrict("web1"); err != nil {
+		t.Errorf("TestRowErrors: could not insert values: %s", err.Error())
+	}
+
+	// Short rows are padded
+	if err := web1.Set("Load", 1); err != nil {
+		t.Errorf("TestRowErrors: could not set the value: %s", err.Error())
+	}
+	if err := web1.Set("Region", "eu"); err == nil {
+		t.Errorf("TestRowErrors: unknown columns should fail")
+	}
+
+	upper := func(v interface{}) interface{} { return strings.ToUpper(v.(string)) }
+	if err := web1.SetModifier(upper, "Host", "Region"); err == nil {
+		t.Errorf("TestRowErrors: unknown columns should fail")
+	}
+	if err := web1.SetModifier(upper, "Host"); err != nil {
+		t.Errorf("TestRowErrors: could not set the modifier: %s", err.Error())
+	}
+
+	// Changing cells of short rows does not panic
+	table.AddRow("web2").Change("Load", 2).Modify(upper, "Host")
+
+	checkValues(t, "TestRowErrors", table, "Host", "web1", Missing)
+	checkValues(t, "TestRowErrors", table, "Load", 1, 2)
+	if out := renderMySQL(table); !strings.Contains(out, "| WEB1 |  1   |") {
+		t.Errorf("TestRowErrors: the modifier should be set:\n%s", out)
+	}
+}
+
+func TestStrictErrors(t *testing.T) {
+
+	table := New("Host", "Load")
+
+	// Errors are only recorded in strict mode
+	web1 := table.AddRow("web1").Insert("web1", 1).Change("Region", "eu")
+	if web1.Err() != nil {
+		t.Errorf("TestStrictErrors: errors should not be recorded outside of strict mode")
+	}
+
+	table.SetStrict(true)
+	rows := []Row{
+		table.AddRow("web2").Insert("web2", 2, "extra"),
+		table.AddRow("web3").Change("Region", "eu"),
+		table.AddRow("web4").Modify(func(v interface{}) interface{} { return v }, "Region"),
+		table.AddRow("web5").Insert("web5", 5).Span("Region", 2, 1),
+		table.AddRow("web1"),
+	}
+	for i, r := range rows {
+		if r.Err() == nil {
+			t.Errorf("TestStrictErrors: expected an error of row %d", i)
+		}
+	}
+
+	// The first error is kept
+	web6 := table.AddRow("web6").Change("Region", "eu").Change("Zone", "a")
+	if err := web6.Err(); err == nil || !strings.Contains(err.Error(), "Region") {
+		t.Errorf("TestStrictErrors: expected the first error, got %v", err)
+	}
+
+	if table.AddRow("web7").Insert("web7", 7).Err() != nil {
+		t.Errorf("TestStrictErrors: unexpected error")
+	}
+}
+
+func TestStrictMerge(t *testing.T) {
+
+	table := New("Host", "Load")
+	table.AddRow("web1").Insert("web1", 1)
+	table.SetStrict(true)
+
+	refresh := New("Host", "Load")
+	refresh.AddRow("web1").Insert("web2", 2)
+
+	err := table.MergeFrom(refresh, MergeOptions{}, "Host")
+	if err == nil || !strings.HasPrefix(err.Error(), "MergeFrom: ") {
+		t.Fatalf("TestStrictMerge: expected a name conflict, got %v", err)
+	}
+
+	web2, _ := table.GetRow(2)
+	if err := web2.Err(); err == nil || !strings.HasPrefix(err.Error(), "Upsert: ") {
+		t.Errorf("TestStrictMerge: expected a recorded name conflict, got %v", err)
+	}
+	if names := strings.Join(table.GetRowNames(), ","); names != "header,web1," {
+		t.Errorf("TestStrictMerge: unexpected row names %s", names)
+	}
+}
@@ -43,6 +43,32 @@ type template struct {
 	F2                      [3]string
 	F3                      [4]string
 	HR                      string
+
+	// HJ joins the walls of two header tiers (defaults to H3[2])
+	HJ string
+
+	// GS separates row groups (no separator if not set)
+	GS [4]string
+
+	// FlatHeaders merges header groups into the column names
+	FlatHeaders bool
+
+	theme Theme
+	spans Spans
+}
+
+// clone returns a copy of the template with its own mutex and column widths
+func (t *template) clone() *template {
+	t.Lock()
+	defer t.Unlock()
+
+	cp := *t
+	cp.Mutex = &sync.Mutex{}
+	cp.ColWidths = []int{}
+	cp.ColWidthOverride = map[int]int{}
+	cp.spans = Spans{}
+
+	return &cp
 }
 
 // SetColumnWidths sets the column widths
@@ -64,6 +90,50 @@ func (t *template) SetColumnContentWidths(width int, columns []int) {
 
 }
 
+// SetSpans sets the merged cells of the next rendered row
+func (t *template) SetSpans(spans Spans) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.spans = spans
+}
+
+// FlattenHeaders returns true if header groups are merged into the column
+// names instead of being rendered as separate header tiers
+func (t *template) FlattenHeaders() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.FlatHeaders
+}
+
+// SetTheme sets the colors and styles of the table regions
+func (t *template) SetTheme(theme Theme) {
+	t.Lock()
+	defer t.Unlock()
+
+	theme.Profile = resolveProfile(theme.Profile)
+	t.theme = theme
+}
+
+// Theme returns the theme of the template
+func (t *template) Theme() Theme {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.theme
+}
+
+// painter returns a painter using the template's theme
+// NB: t must be locked
+func (t *template) painter(cell func(col int) Style) painter {
+	return painter{
+		profile: t.theme.Profile,
+		border:  t.theme.Border,
+		cell:    cell,
+	}
+}
+
 // SetDisplayOptions sets some display options
 func (t *template) SetDisplayOptions(center bool) {
 	t.Lock()
@@ -78,7 +148,20 @@ func (t *template) RenderHeader(mcells, pcells []string) []string {
 	defer t.Unlock()
 
 	// Render lines
-	L1, L2, L3, _ := renderL1L2L3(t.H1, t.H2, t.H3, t.ColWidths, map[int]int{}, mcells, pcells, t.Center)
+	L1, L2, L3, _ := renderL1L2L3(t.H1, t.H2, t.H3, t.ColWidths, map[int]int{}, mcells, pcells, t.spans, t.Center, t.painter(func(int) Style { return t.theme.Header }))
+
+	// Header tiers are joined instead of being closed
+	if t.spans.Above != nil {
+		hj := t.HJ
+		if hj == "" {
+			hj = t.H3[2]
+		}
+		L1 = renderJoint(t.H1, t.H3, hj, t.ColWidths, Spans{Cols: t.spans.Above}, t.spans)
+		L1 = t.theme.Border.Sprint(L1, t.theme.Profile)
+		if t.Center {
+			L1 = centerStr(L1)
+		}
+	}
 
 	// Append or skip
 	lines := []string{}
@@ -86,7 +169,7 @@ func (t *template) RenderHeader(mcells, pcells []string) []string {
 		lines = append(lines, L1)
 	}
 	lines = append(lines, L2)
-	if !t.SkipH3 {
+	if !t.SkipH3 && !t.spans.Below {
 		lines = append(lines, L3)
 	}
 
@@ -99,8 +182,17 @@ func (t *template) RenderRow(row, rows int, mcells, pcells []string) []string {
 	t.Lock()
 	defer t.Unlock()
 
+	// Zebra stripes and column styles
+	rowStyle := t.theme.Rows
+	if row%2 == 0 {
+		rowStyle = t.theme.AltRows
+	}
+	cellStyle := func(col int) Style {
+		return rowStyle.Merge(t.theme.Columns[col])
+	}
+
 	// Render lines
-	L1, L2, L3, _ := renderL1L2L3(t.C1, t.C2, t.C3, t.ColWidths, t.ColWidthOverride, mcells, pcells, t.Center)
+	L1, L2, L3, _ := renderL1L2L3(t.C1, t.C2, t.C3, t.ColWidths, t.ColWidthOverride, mcells, pcells, t.spans, t.Center, t.painter(cellStyle))
 
 	lines := []string{}
 	if !t.SkipC1 && (row != 1 || !t.SkipFirstC1) {
@@ -117,24 +209,102 @@ func (t *template) RenderRow(row, rows int, mcells, pcells []string) []string {
 
 }
 
+// RenderGroupHeader renders the title of a row group across all the columns
+func (t *template) RenderGroupHeader(title string) []string {
+	t.Lock()
+	defer t.Unlock()
+
+	first := firstColumn(t.ColWidths)
+	if first == -1 {
+		return []string{title}
+	}
+
+	// Merge all the columns and left-align the title
+	width := -3
+	for _, w := range t.ColWidths {
+		if w != 0 {
+			width += w + 3
+		}
+	}
+	if length := visibleLength(title); length < width {
+		title += strings.Repeat(" ", width-length)
+	}
+
+	cells := make([]string, len(t.ColWidths))
+	cells[first] = title
+	spans := mergedSpans(t.ColWidths)
+	spans.Next = t.spans.Next
+
+	_, L2, L3, _ := renderL1L2L3(t.C1, t.C2, t.C3, t.ColWidths, map[int]int{}, cells, cells, spans, t.Center, t.painter(func(int) Style { return t.theme.Header }))
+
+	lines := []string{L2}
+	if !t.SkipC3 {
+		lines = append(lines, L3)
+	}
+
+	return lines
+}
+
+// RenderSubtotal renders the subtotal row of a row group
+func (t *template) RenderSubtotal(mcells, pcells []string) []string {
+	t.Lock()
+	defer t.Unlock()
+
+	L1, L2, _, _ := renderL1L2L3(t.C1, t.C2, t.C3, t.ColWidths, t.ColWidthOverride, mcells, pcells, t.spans, t.Center, t.painter(func(int) Style { return t.theme.Footer }))
+
+	return []string{L1, L2}
+}
+
+// RenderGroupSeparator renders the line between two row groups
+func (t *template) RenderGroupSeparator() []string {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.GS == [4]string{} {
+		return []string{}
+	}
+
+	line := renderJoint(t.GS, t.GS, t.GS[2], t.ColWidths, Spans{}, Spans{})
+	line = t.theme.Border.Sprint(line, t.theme.Profile)
+	if t.Center {
+		line = centerStr(line)
+	}
+
+	return []string{line}
+}
+
 // RenderFooter renders the footer row
 func (t *template) RenderFooter(mcells, pcells []string) []string {
 	t.Lock()
 	defer t.Unlock()
 
 	// Render lines
-	L1, L2, L3, isEmpty := renderL1L2L3(t.F1, t.F2, t.F3, t.ColWidths, map[int]int{}, mcells, pcells, t.Center)
+	L1, L2, L3, isEmpty := renderL1L2L3(t.F1, t.F2, t.F3, t.ColWidths, map[int]int{}, mcells, pcells, t.spans, t.Center, t.painter(func(int) Style { return t.theme.Footer }))
 
+	// Empty footers (e.g. tables without a footer) only close the table: with
+	// the bottom corners of F3 (if visible) and the junctions of F1
 	lines := []string{}
-	if !t.SkipF1 {
+	switch {
+	case isEmpty && t.SkipF1:
+	case isEmpty && !t.SkipF3 && strings.TrimSpace(strings.Join(t.F3[:], "")) != "":
+		closing := t.F3
+		closing[2] = t.F1[2]
+		line := renderBorder(closing, t.F2, t.ColWidths, t.spans.Prev, nil, make([]bool, len(t.ColWidths)))
+		line = t.theme.Border.Sprint(line, t.theme.Profile)
+		if t.Center {
+			line = centerStr(line)
+		}
+		lines = append(lines, line)
+	case isEmpty:
 		lines = append(lines, L1)
-	}
-	if !isEmpty {
+	default:
+		if !t.SkipF1 {
+			lines = append(lines, L1)
+		}
 		lines = append(lines, L2)
-	}
-
-	if !t.SkipF3 && !isEmpty {
-		lines = append(lines, L3)
+		if !t.SkipF3 {
+			lines = append(lines, L3)
+		}
 	}
 
 	return lines
@@ -142,6 +312,8 @@ func (t *template) RenderFooter(mcells, pcells []string) []string {
 
 // RenderFootnotes renders footnotes
 func (t *template) RenderFootnotes(footnotes []string) []string {
+	t.Lock()
+	defer t.Unlock()
 
 	lines := []string{"", "<HR>"}
 
@@ -151,15 +323,75 @@ func (t *template) RenderFootnotes(footnotes []string) []string {
 		if length := utf8.RuneCountInString(formatted); length > longest {
 			longest = length
 		}
-		lines = append(lines, formatted)
+		lines = append(lines, t.theme.Footnote.Sprint(formatted, t.theme.Profile))
 	}
 
-	lines[1] = strings.Repeat(t.HR, longest)
+	lines[1] = t.theme.Footnote.Sprint(strings.Repeat(t.HR, longest), t.theme.Profile)
 	lines = append(lines, "", "")
 
 	return lines
 }
 
+// RenderRecord renders a single row in the expanded mode, e.g.
+//
+// ─[ RECORD 3: rowname ]───────
+// Year       │ 1998
+// GDP growth │ 7.4671760033
+func (t *template) RenderRecord(label string, mkeys, pkeys, mcells, pcells []string) []string {
+	t.Lock()
+	defer t.Unlock()
+
+	keyWidth, valueWidth := 0, 0
+	if len(t.ColWidths) == 2 {
+		keyWidth, valueWidth = t.ColWidths[0], t.ColWidths[1]
+	}
+
+	// Border characters
+	fill := t.C3[1]
+	if strings.TrimSpace(fill) == "" {
+		fill = t.HR
+	}
+	wall := t.C2[1]
+
+	// Record separator
+	width := keyWidth + valueWidth + 3
+	sep := fmt.Sprintf("%s[ %s ]", fill, label)
+	if length := utf8.RuneCountInString(sep); length < width {
+		sep += strings.Repeat(fill, width-length)
+	}
+
+	lines := []string{t.theme.Border.Sprint(sep, t.theme.Profile)}
+	wall = t.theme.Border.Sprint(wall, t.theme.Profile)
+
+	// Key-value pairs
+	for i := range pcells {
+		key, mkey := "", ""
+		if i < len(pkeys) {
+			key, mkey = pkeys[i], mkeys[i]
+		}
+		keySpace := strings.Repeat(" ", keyWidth-utf8.RuneCountInString(mkey))
+		key = t.theme.Header.Sprint(key, t.theme.Profile)
+
+		for j, part := range strings.Split(pcells[i], "\n") {
+			part = t.theme.Columns[i].Sprint(part, t.theme.Profile)
+			if j == 0 {
+				lines = append(lines, fmt.Sprintf("%s%s %s %s", key, keySpace, wall, part))
+			} else {
+				lines = append(lines, fmt.Sprintf("%s %s %s", strings.Repeat(" ", keyWidth), wall, part))
+			}
+		}
+	}
+
+	if t.Center {
+		offset := strings.Repeat(" ", getOffset(width))
+		for i := range lines {
+			lines[i] = offset + lines[i]
+		}
+	}
+
+	return lines
+}
+
 // RenderTitles renders the title
 func (t *template) RenderTitles(titles []string) []string {
 	t.Lock()
@@ -168,6 +400,7 @@ func (t *template) RenderTitles(titles []string) []string {
 	lines := []string{""}
 
 	for _, title := range titles {
+		title = t.theme.Title.Sprint(title, t.theme.Profile)
 		if t.Center {
 			lines = append(lines, centerStr(title))
 		} else {
@@ -182,44 +415,63 @@ func (t *template) RenderTitles(titles []string) []string {
 
 // PrintExample Prints an example
 func (t *template) PrintExample(dst io.Writer) {
+	exampleTable().Render(dst, false, true, true, t)
+}
+
+// exampleTable builds the table used by PrintExample
+func exampleTable() Table {
 
 	table := New("ID", "Client", "Amount")
-	table.AddRow("").Insert(1, "Dunder Mifflin", "172,341")
-	table.AddRow("").Insert(2, "Acme Corporation", "43,223")
-	table.AddRow("").Insert(3, "Monsters, Inc", "666,666")
-	table.AddRow("").Insert(4, "Advanced Idea Mechanics", "469,218")
-	table.AddRow("").Insert(5, "Michael Scott Paper Company", "9,288")
-	table.AddRow("").Insert(6, "Weyland-Yutani Corporation", "982,283,767")
+	table.AddRow("").Insert(1, "Dunder Mifflin", 172341)
+	table.AddRow("").Insert(2, "Acme Corporation", 43223)
+	table.AddRow("").Insert(3, "Monsters, Inc", 666666)
+	table.AddRow("").Insert(4, "Advanced Idea Mechanics", 469218)
+	table.AddRow("").Insert(5, "Michael Scott Paper Company", 9288)
+	table.AddRow("").Insert(6, "Weyland-Yutani Corporation", 982283767)
 	table.AddFooter().Insert("","Total:","983,644,505 (983.6m)")
 
 	table.SetFormat("%-27s", "Client")
 	table.SetFormat("%20s","Amount")
+	table.SetFormatter(MustLoadLocale("en-US").Number(0), "Amount")
 
-	table.Render(dst, false, true, true, t)
+	return table
 }
 
 // renderL1L2L3 renders a template line
-func renderL1L2L3(T1 [4]string, T2 [3]string, T3 [4]string, widths []int, contentWidths map[int]int, mcells, pcells []string, center bool) (L1 string, L2 string, L3 string, isEmpty bool) {
+func renderL1L2L3(T1 [4]string, T2 [3]string, T3 [4]string, widths []int, contentWidths map[int]int, mcells, pcells []string, spans Spans, center bool, paint painter) (L1 string, L2 string, L3 string, isEmpty bool) {
 
 	var tlsum int
 	lines := newLines(pcells)
 	L2Slice := []string{}
+	isEmpty = true
 	for line := 1; line <= lines; line++ {
 
-		L1 = T1[0]
-		L2 = T2[0]
-		L3 = T3[0]
+		L2 = paint.borders(T2[0])
 
 		tlsum = 1
-		for i, width := range widths {
+		for i := 0; i < len(widths); i++ {
+			width := widths[i]
 
-			// Skip irrelevant columns
-			if width == 0 {
+			// Skip irrelevant columns and merged cells
+			span := spans.cols(i)
+			if width == 0 || span == 0 {
 				continue
 			}
 
+			// Cells spanning several columns (the inner walls are merged)
+			last := i
+			for k := i + 1; k < i+span && k < len(widths); k++ {
+				if widths[k] != 0 {
+					width += widths[k] + 3
+					last = k
+				}
+			}
+
 			// Cell values and spacing
 			cwidth, _ := contentWidths[i]
+			if last != i {
+				cwidth = 0
+			}
 			value, sp1, sp2, tl := measure(i, width, cwidth, mcells, pcells)
 
 			// Cell lines and prelines (empty lines)
@@ -229,36 +481,27 @@ func renderL1L2L3(T1 [4]string, T2 [3]string, T3 [4]string, widths []int, conten
 				prelines = int((lines - ilines) / 2)
 			}
 
-			// Upper border
-			L1 += strings.Repeat(T1[1], width+2)
-
 			// Prelines, lines, postlines
 			if line <= prelines || line > prelines+ilines {
-				L2 += fmt.Sprintf("%s",strings.Repeat(" ",width+2))
+				L2 += paint.cells(i, strings.Repeat(" ",width+2))
 			}else{
 				valueParts := strings.Split(value, "\n")
 				sp1Parts := strings.Split(sp1, "\n")
 				sp2Parts := strings.Split(sp2, "\n")
 
 				iline := line-prelines-1
-				L2 += fmt.Sprintf("%s%s%s", sp1Parts[iline], valueParts[iline], sp2Parts[iline])
+				L2 += paint.cells(i, fmt.Sprintf("%s%s%s", sp1Parts[iline], valueParts[iline], sp2Parts[iline]))
 			}
 
-			// Bottom border
-			L3 += strings.Repeat(T3[1], width+2)
-
 			// Cell walls to the right
+			i = last
 			if i != len(widths)-1 {
-				L1 += T1[2]
-				L2 += T2[1]
-				L3 += T3[2]
+				L2 += paint.borders(T2[1])
 			} else {
-				L1 += T1[3]
-				L2 += T2[2]
-				L3 += T3[3]
+				L2 += paint.borders(T2[2])
 			}
 
-			if len(value) > 0 {
+			if strings.TrimSpace(value) != "" {
 				isEmpty = false
 			}
 
@@ -272,11 +515,18 @@ func renderL1L2L3(T1 [4]string, T2 [3]string, T3 [4]string, widths []int, conten
 		}else{
 			L2Slice = append(L2Slice, L2)
 		}
-			L2 = T2[0]
 		}
 
 	}
 
+	// Borders join the walls of the rows above and below
+	cols := spans.columns(len(widths))
+	L1 = renderBorder(T1, T2, widths, spans.Prev, cols, spans.opened(spans.up, len(widths)))
+	L3 = renderBorder(T3, T2, widths, cols, spans.Next, spans.opened(spans.down, len(widths)))
+
+	L1 = paint.borders(L1)
+	L3 = paint.borders(L3)
+
 	if center {
 		L1 = centerStr(L1)
 		L3 = centerStr(L3)
@@ -288,6 +538,164 @@ func renderL1L2L3(T1 [4]string, T2 [3]string, T3 [4]string, widths []int, conten
 
 }
 
+// renderBorder renders the border between two rows of the column spans above
+// and below (nil if there is no row). Junctions lose their arms towards merged
+// cells and missing rows (e.g. "┼" becomes "┬" below a cell spanning several
+// columns), open cells continuing across the border (row spans) are not
+// crossed by the line.
+func renderBorder(T [4]string, T2 [3]string, widths []int, above, below []int, open []bool) string {
+
+	first := firstColumn(widths)
+	if first == -1 {
+		return T[0]
+	}
+
+	line := corner(T[0], T2[0], open[first])
+	for i, width := range widths {
+		if width == 0 {
+			continue
+		}
+
+		if open[i] {
+			line += strings.Repeat(" ", width+2)
+		} else {
+			line += strings.Repeat(T[1], width+2)
+		}
+
+		if i == len(widths)-1 {
+			line += corner(T[3], T2[2], open[i])
+			continue
+		}
+
+		// Junction with the next rendered column
+		next := i + 1
+		for next < len(widths) && widths[next] == 0 {
+			next++
+		}
+		if next == len(widths) {
+			line += T[2]
+			continue
+		}
+
+		up := next < len(above) && above[next] != 0
+		down := next < len(below) && below[next] != 0
+		line += junction(T[2], T[1], T2[1], up, down, !open[i], !open[next])
+	}
+
+	return line
+}
+
+// renderJoint renders the line between two header tiers. Walls of both tiers
+// are joined by hj, walls of the upper tier by H3 junctions and walls of the
+// lower tier by H1 junctions.
+func renderJoint(H1, H3 [4]string, hj string, widths []int, above, below Spans) string {
+
+	line := H3[0]
+	first := true
+	for i, width := range widths {
+		if width == 0 {
+			continue
+		}
+
+		if !first {
+			upper, lower := above.cols(i) != 0, below.cols(i) != 0
+			switch {
+			case upper && lower:
+				line += hj
+			case upper:
+				line += H3[2]
+			case lower:
+				line += H1[2]
+			default:
+				line += H3[1]
+			}
+		}
+		first = false
+
+		line += strings.Repeat(H3[1], width+2)
+	}
+
+	return line + H3[3]
+}
+
+// firstColumn returns the index of the first relevant column
+func firstColumn(widths []int) int {
+	for i, width := range widths {
+		if width != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// corner returns the wall instead of the corner next to open borders
+func corner(corner, wall string, open bool) string {
+	if open {
+		return wall
+	}
+	return corner
+}
+
+// boxShapes lists the arms (up, down, left, right) of the box-drawing
+// characters in the order of boxFamilies
+var boxShapes = [11][4]bool{
+	{false, false, true, true}, {true, true, false, false},
+	{false, true, false, true}, {false, true, true, false}, {true, false, false, true}, {true, false, true, false},
+	{true, true, false, true}, {true, true, true, false}, {false, true, true, true}, {true, false, true, true},
+	{true, true, true, true},
+}
+
+// boxFamilies lists box-drawing characters of the same line weights
+var boxFamilies = [][]rune{
+	[]rune("─│┌┐└┘├┤┬┴┼"),
+	[]rune("━┃┏┓┗┛┣┫┳┻╋"),
+	[]rune("═║╔╗╚╝╠╣╦╩╬"),
+	[]rune("═│╒╕╘╛╞╡╤╧╪"),
+	[]rune("─║╓╖╙╜╟╢╥╨╫"),
+	[]rune("━│┍┑┕┙┝┥┯┷┿"),
+	[]rune("─┃┎┒┖┚┠┨┰┸╂"),
+}
+
+// junction returns the junction glyph without the missing arms, the line if
+// there are no walls above and below and the wall if there are no lines to
+// the left and right. Glyphs other than box-drawing characters (e.g. "+") are
+// only replaced by the line or the wall.
+func junction(glyph, line, wall string, up, down, left, right bool) string {
+
+	switch {
+	case !left && !right && !up && !down:
+		return strings.Repeat(" ", utf8.RuneCountInString(glyph))
+	case !left && !right:
+		return wall
+	case !up && !down:
+		return line
+	}
+
+	runes := []rune(glyph)
+	if len(runes) != 1 {
+		return glyph
+	}
+
+	for _, family := range boxFamilies {
+		for k := 2; k < len(family); k++ {
+			if family[k] != runes[0] {
+				continue
+			}
+
+			arms := boxShapes[k]
+			arms = [4]bool{arms[0] && up, arms[1] && down, arms[2] && left, arms[3] && right}
+			for n, shape := range boxShapes {
+				if shape == arms {
+					return string(family[n])
+				}
+			}
+			return glyph
+		}
+	}
+
+	return glyph
+}
+
 // mesure mesaures string widths and returns printable strings
 func measure(i, width int, contentWidth int, mcells, pcells []string) (string, string, string, int) {
 
@@ -347,7 +755,7 @@ func getOffset(width int) int {
 
 // centerStr centers a string
 func centerStr(value string) string {
-	width := utf8.RuneCountInString(value)
+	width := visibleLength(value)
 	offset := getOffset(width)
 
 	return fmt.Sprintf("%s%s", strings.Repeat(" ", offset), value)
@@ -366,12 +774,14 @@ func tmplClassic() *template {
 		H1:         [4]string{"╔", "═", "╦", "╗"},
 		H2:         [3]string{"║", "║", "║"},
 		H3:         [4]string{"╠", "═", "╩", "╣"},
+		HJ:         "╬",
 		C1:         [4]string{"╟", "─", "┼", "╢"},
 		C2:         [3]string{"║", "│", "║"},
 		C3:         [4]string{"╟", "─", "┼", "╢"},
 		F1:         [4]string{"╚", "═", "╧", "╝"},
 		F2:         [3]string{" ", " ", " "},
 		F3:         [4]string{" ", " ", " ", " "},
+		GS:         [4]string{"╠", "═", "╪", "╣"},
 		HR:         "─",
 	}
 }
@@ -394,6 +804,7 @@ func tmplSmooth() *template {
 		F1:         [4]string{"├", "─", "┴", "┤"},
 		F2:         [3]string{"│", " ", "│"},
 		F3:         [4]string{"╰", "─", "─", "╯"},
+		GS:         [4]string{"┝", "━", "┿", "┥"},
 		HR:         "─",
 	}
 }
@@ -417,6 +828,320 @@ func tmplModern() *template {
 		F1:         [4]string{"━", "━", "━", "━"},
 		F2:         [3]string{" ", " ", " "},
 		F3:         [4]string{" ", " ", " ", " "},
+		GS:         [4]string{"━", "━", "━", "━"},
 		HR:         "─",
 	}
 }
+
+// ASCII template (+-|) for legacy terminals and log files
+func tmplASCII() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipLastC3:       true,
+		SkipF3:           true,
+		H1:               [4]string{"+", "=", "+", "+"},
+		H2:               [3]string{"|", "|", "|"},
+		H3:               [4]string{"+", "=", "+", "+"},
+		C1:               [4]string{"+", "-", "+", "+"},
+		C2:               [3]string{"|", "|", "|"},
+		C3:               [4]string{"+", "-", "+", "+"},
+		F1:               [4]string{"+", "=", "+", "+"},
+		F2:               [3]string{" ", " ", " "},
+		F3:               [4]string{" ", " ", " ", " "},
+		GS:               [4]string{"+", "=", "+", "+"},
+		HR:               "-",
+	}
+}
+
+// Double-line template
+func tmplDouble() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipLastC3:       true,
+		SkipF3:           true,
+		H1:               [4]string{"╔", "═", "╦", "╗"},
+		H2:               [3]string{"║", "║", "║"},
+		H3:               [4]string{"╠", "═", "╬", "╣"},
+		C1:               [4]string{"╠", "═", "╬", "╣"},
+		C2:               [3]string{"║", "║", "║"},
+		C3:               [4]string{"╠", "═", "╬", "╣"},
+		F1:               [4]string{"╚", "═", "╩", "╝"},
+		F2:               [3]string{" ", " ", " "},
+		F3:               [4]string{" ", " ", " ", " "},
+		GS:               [4]string{"╠", "═", "╬", "╣"},
+		HR:               "═",
+	}
+}
+
+// Heavy-line template
+func tmplHeavy() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipLastC3:       true,
+		SkipF3:           true,
+		H1:               [4]string{"┏", "━", "┳", "┓"},
+		H2:               [3]string{"┃", "┃", "┃"},
+		H3:               [4]string{"┣", "━", "╋", "┫"},
+		C1:               [4]string{"┣", "━", "╋", "┫"},
+		C2:               [3]string{"┃", "┃", "┃"},
+		C3:               [4]string{"┣", "━", "╋", "┫"},
+		F1:               [4]string{"┗", "━", "┻", "┛"},
+		F2:               [3]string{" ", " ", " "},
+		F3:               [4]string{" ", " ", " ", " "},
+		GS:               [4]string{"┣", "━", "╋", "┫"},
+		HR:               "━",
+	}
+}
+
+// Rounded template without row separators
+func tmplRoundedCompact() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipC3:           true,
+		SkipF3:           true,
+		H1:               [4]string{"╭", "─", "┬", "╮"},
+		H2:               [3]string{"│", "│", "│"},
+		H3:               [4]string{"├", "─", "┼", "┤"},
+		C1:               [4]string{"├", "─", "┼", "┤"},
+		C2:               [3]string{"│", "│", "│"},
+		C3:               [4]string{"├", "─", "┼", "┤"},
+		F1:               [4]string{"╰", "─", "┴", "╯"},
+		F2:               [3]string{" ", " ", " "},
+		F3:               [4]string{" ", " ", " ", " "},
+		GS:               [4]string{"┝", "━", "┿", "┥"},
+		HR:               "─",
+	}
+}
+
+// Dotted template
+func tmplDotted() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipLastC3:       true,
+		SkipF3:           true,
+		H1:               [4]string{"┌", "┄", "┬", "┐"},
+		H2:               [3]string{"┆", "┆", "┆"},
+		H3:               [4]string{"├", "┄", "┼", "┤"},
+		C1:               [4]string{"├", "┄", "┼", "┤"},
+		C2:               [3]string{"┆", "┆", "┆"},
+		C3:               [4]string{"├", "┄", "┼", "┤"},
+		F1:               [4]string{"└", "┄", "┴", "┘"},
+		F2:               [3]string{" ", " ", " "},
+		F3:               [4]string{" ", " ", " ", " "},
+		GS:               [4]string{"├", "─", "┼", "┤"},
+		HR:               "┄",
+	}
+}
+
+// Borderless template (similar to column -t)
+func tmplPlain() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipH1:           true,
+		SkipH3:           true,
+		SkipC1:           true,
+		SkipC3:           true,
+		SkipF1:           true,
+		SkipF3:           true,
+		H1:               [4]string{"", " ", " ", ""},
+		H2:               [3]string{"", " ", ""},
+		H3:               [4]string{"", " ", " ", ""},
+		C1:               [4]string{"", " ", " ", ""},
+		C2:               [3]string{"", " ", ""},
+		C3:               [4]string{"", " ", " ", ""},
+		F1:               [4]string{"", " ", " ", ""},
+		F2:               [3]string{"", " ", ""},
+		F3:               [4]string{"", " ", " ", ""},
+		HR:               "─",
+	}
+}
+
+// Minimal template (horizontal rules only)
+func tmplMinimal() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipC3:           true,
+		SkipF3:           true,
+		H1:               [4]string{"", "─", "─", ""},
+		H2:               [3]string{"", " ", ""},
+		H3:               [4]string{"", "─", "─", ""},
+		C1:               [4]string{"", " ", " ", ""},
+		C2:               [3]string{"", " ", ""},
+		C3:               [4]string{"", " ", " ", ""},
+		F1:               [4]string{"", "─", "─", ""},
+		F2:               [3]string{"", " ", ""},
+		F3:               [4]string{"", " ", " ", ""},
+		GS:               [4]string{"", "━", "━", ""},
+		HR:               "─",
+	}
+}
+
+// PostgreSQL (psql) template
+func tmplPSQL() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipH1:           true,
+		SkipC1:           true,
+		SkipC3:           true,
+		SkipF1:           true,
+		SkipF3:           true,
+		H1:               [4]string{"", " ", " ", ""},
+		H2:               [3]string{"", "|", ""},
+		H3:               [4]string{"", "-", "+", ""},
+		C1:               [4]string{"", " ", " ", ""},
+		C2:               [3]string{"", "|", ""},
+		C3:               [4]string{"", " ", " ", ""},
+		F1:               [4]string{"", " ", " ", ""},
+		F2:               [3]string{"", "|", ""},
+		F3:               [4]string{"", " ", " ", ""},
+		GS:               [4]string{"", "=", "+", ""},
+		HR:               "-",
+	}
+}
+
+// MySQL template
+func tmplMySQL() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipC3:           true,
+		H1:               [4]string{"+", "-", "+", "+"},
+		H2:               [3]string{"|", "|", "|"},
+		H3:               [4]string{"+", "-", "+", "+"},
+		C1:               [4]string{"+", "-", "+", "+"},
+		C2:               [3]string{"|", "|", "|"},
+		C3:               [4]string{"+", "-", "+", "+"},
+		F1:               [4]string{"+", "-", "+", "+"},
+		F2:               [3]string{"|", "|", "|"},
+		F3:               [4]string{"+", "-", "+", "+"},
+		GS:               [4]string{"+", "=", "+", "+"},
+		HR:               "-",
+	}
+}
+
+// reStructuredText grid table template
+func tmplRSTGrid() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipF1:           true,
+		H1:               [4]string{"+", "-", "+", "+"},
+		H2:               [3]string{"|", "|", "|"},
+		H3:               [4]string{"+", "=", "+", "+"},
+		C1:               [4]string{"+", "-", "+", "+"},
+		C2:               [3]string{"|", "|", "|"},
+		C3:               [4]string{"+", "-", "+", "+"},
+		F1:               [4]string{"+", "-", "+", "+"},
+		F2:               [3]string{"|", "|", "|"},
+		F3:               [4]string{"+", "-", "+", "+"},
+		GS:               [4]string{"+", "=", "+", "+"},
+		HR:               "-",
+	}
+}
+
+// reStructuredText simple table template
+func tmplRSTSimple() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipC3:           true,
+		H1:               [4]string{"", "=", " ", ""},
+		H2:               [3]string{"", " ", ""},
+		H3:               [4]string{"", "=", " ", ""},
+		C1:               [4]string{"", " ", " ", ""},
+		C2:               [3]string{"", " ", ""},
+		C3:               [4]string{"", " ", " ", ""},
+		F1:               [4]string{"", "-", " ", ""},
+		F2:               [3]string{"", " ", ""},
+		F3:               [4]string{"", "=", " ", ""},
+		HR:               "=",
+	}
+}
+
+// Org-mode template
+func tmplOrg() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipC1:           true,
+		SkipC3:           true,
+		H1:               [4]string{"|", "-", "+", "|"},
+		H2:               [3]string{"|", "|", "|"},
+		H3:               [4]string{"|", "-", "+", "|"},
+		C1:               [4]string{"|", "-", "+", "|"},
+		C2:               [3]string{"|", "|", "|"},
+		C3:               [4]string{"|", "-", "+", "|"},
+		F1:               [4]string{"|", "-", "+", "|"},
+		F2:               [3]string{"|", "|", "|"},
+		F3:               [4]string{"|", "-", "+", "|"},
+		GS:               [4]string{"|", "-", "+", "|"},
+		HR:               "-",
+	}
+}
+
+// Markdown template
+func tmplMarkdown() *template {
+
+	return &template{
+		Mutex:            &sync.Mutex{},
+		ColWidths:        []int{},
+		ColWidthOverride: map[int]int{},
+		SkipH1:           true,
+		SkipC1:           true,
+		SkipC3:           true,
+		SkipF1:           true,
+		SkipF3:           true,
+		FlatHeaders:      true,
+		H1:               [4]string{"|", "-", "|", "|"},
+		H2:               [3]string{"|", "|", "|"},
+		H3:               [4]string{"|", "-", "|", "|"},
+		C1:               [4]string{"|", "-", "|", "|"},
+		C2:               [3]string{"|", "|", "|"},
+		C3:               [4]string{"|", "-", "|", "|"},
+		F1:               [4]string{"|", "-", "|", "|"},
+		F2:               [3]string{"|", "|", "|"},
+		F3:               [4]string{"|", "-", "|", "|"},
+		HR:               "-",
+	}
+}
@@ -0,0 +1,79 @@
+package lentele
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// builtinTemplates lists the templates shipped with the package
+var builtinTemplates = []string{
+	"ascii", "classic", "dotted", "double", "heavy", "markdown", "minimal", "modern",
+	"mysql", "org", "plain", "psql", "rounded-compact", "rst-grid", "rst-simple", "smooth",
+}
+
+func TestTemplatesGolden(t *testing.T) {
+
+	// Tables without a footer are only closed by the templates
+	variants := map[string]func() Table{
+		"":          exampleTable,
+		"-nofooter": noFooterTable,
+	}
+
+	for _, name := range builtinTemplates {
+		for suffix, build := range variants {
+			tmpl, err := LoadTemplate(name)
+			if err != nil {
+				t.Errorf("TestTemplatesGolden: could not load template '%s': %s", name, err.Error())
+				continue
+			}
+
+			out := bytes.NewBuffer([]byte{})
+			build().Render(out, false, true, false, tmpl)
+
+			checkGolden(t, "TestTemplatesGolden", name+suffix, out.Bytes())
+		}
+	}
+}
+
+// checkGolden compares the output with the golden file testdata/<name>.golden
+// (or updates the file if the update flag is set)
+func checkGolden(t *testing.T, test, name string, out []byte) {
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, out, 0644); err != nil {
+			t.Fatalf("%s: could not update golden file: %s", test, err.Error())
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Errorf("%s: could not read golden file '%s': %s", test, golden, err.Error())
+		return
+	}
+
+	if !bytes.Equal(out, expected) {
+		t.Errorf("%s: output does not match the golden file '%s':\n%s", test, golden, string(out))
+	}
+}
+
+// noFooterTable builds the example table without its footer
+func noFooterTable() Table {
+
+	table := New("ID", "Client", "Amount")
+	table.AddRow("").Insert(1, "Dunder Mifflin", 172341)
+	table.AddRow("").Insert(2, "Acme Corporation", 43223)
+	table.AddRow("").Insert(3, "Monsters, Inc", 666666)
+
+	table.SetFormat("%-27s", "Client")
+	table.SetFormat("%20s", "Amount")
+	table.SetFormatter(MustLoadLocale("en-US").Number(0), "Amount")
+
+	return table
+}
@@ -0,0 +1,396 @@
+package lentele
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ColorProfile describes the color capabilities of the output
+type ColorProfile int
+
+// Supported color profiles
+const (
+	ProfileAuto      ColorProfile = iota // Detect the profile (see DetectColorProfile)
+	ProfileNone                          // No colors or text attributes
+	ProfileANSI                          // 16 colors
+	ProfileANSI256                       // 256 colors
+	ProfileTrueColor                     // 24-bit colors
+)
+
+// ColorMode describes how a color has been defined
+type ColorMode int
+
+// Supported color modes
+const (
+	ColorDefault ColorMode = iota // Terminal's default color
+	Color16                       // One of the 16 basic ANSI colors
+	Color256                      // One of the 256 xterm colors
+	ColorRGB                      // 24-bit color
+)
+
+// Color is a foreground or background color. The zero value is the terminal's
+// default color.
+type Color struct {
+	Mode  ColorMode `json:"mode"`
+	Value uint32    `json:"value"`
+}
+
+// Basic ANSI colors
+var (
+	Black         = ANSIColor(0)
+	Red           = ANSIColor(1)
+	Green         = ANSIColor(2)
+	Yellow        = ANSIColor(3)
+	Blue          = ANSIColor(4)
+	Magenta       = ANSIColor(5)
+	Cyan          = ANSIColor(6)
+	White         = ANSIColor(7)
+	BrightBlack   = ANSIColor(8)
+	BrightRed     = ANSIColor(9)
+	BrightGreen   = ANSIColor(10)
+	BrightYellow  = ANSIColor(11)
+	BrightBlue    = ANSIColor(12)
+	BrightMagenta = ANSIColor(13)
+	BrightCyan    = ANSIColor(14)
+	BrightWhite   = ANSIColor(15)
+)
+
+// ANSIColor returns one of the 16 basic ANSI colors (0-15)
+func ANSIColor(n int) Color {
+	return Color{Mode: Color16, Value: uint32(clampInt(n, 0, 15))}
+}
+
+// XtermColor returns one of the 256 xterm colors (0-255)
+func XtermColor(n int) Color {
+	return Color{Mode: Color256, Value: uint32(clampInt(n, 0, 255))}
+}
+
+// RGB returns a 24-bit color
+func RGB(r, g, b uint8) Color {
+	return Color{Mode: ColorRGB, Value: uint32(r)<<16 | uint32(g)<<8 | uint32(b)}
+}
+
+// IsDefault returns true if the color is the terminal's default color
+func (c Color) IsDefault() bool {
+	return c.Mode == ColorDefault
+}
+
+// sgr returns the SGR parameters of the color downgraded to the profile
+func (c Color) sgr(profile ColorProfile, background bool) string {
+
+	if c.Mode == ColorDefault || profile == ProfileNone {
+		return ""
+	}
+
+	// Downgrade the color
+	mode, value := c.Mode, c.Value
+	switch {
+	case mode == ColorRGB && profile == ProfileANSI256:
+		mode, value = Color256, uint32(rgbTo256(value))
+	case mode == ColorRGB && profile == ProfileANSI:
+		mode, value = Color16, uint32(nearestANSI(value))
+	case mode == Color256 && profile == ProfileANSI:
+		if value >= 16 {
+			mode, value = Color16, uint32(nearestANSI(xtermToRGB(int(value))))
+		} else {
+			mode = Color16
+		}
+	}
+
+	switch mode {
+	case Color16:
+		base := 30
+		if background {
+			base = 40
+		}
+		if value >= 8 {
+			return fmt.Sprintf("%d", base+60+int(value)-8)
+		}
+		return fmt.Sprintf("%d", base+int(value))
+
+	case Color256:
+		if background {
+			return fmt.Sprintf("48;5;%d", value)
+		}
+		return fmt.Sprintf("38;5;%d", value)
+
+	default:
+		r, g, b := value>>16&0xff, value>>8&0xff, value&0xff
+		if background {
+			return fmt.Sprintf("48;2;%d;%d;%d", r, g, b)
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	}
+}
+
+// Style describes the colors and attributes of a piece of text. The zero value
+// leaves the text unchanged.
+type Style struct {
+	Fg        Color `json:"fg"`
+	Bg        Color `json:"bg"`
+	Bold      bool  `json:"bold,omitempty"`
+	Dim       bool  `json:"dim,omitempty"`
+	Italic    bool  `json:"italic,omitempty"`
+	Underline bool  `json:"underline,omitempty"`
+	Reverse   bool  `json:"reverse,omitempty"`
+}
+
+// IsZero returns true if the style does not change the text
+func (s Style) IsZero() bool {
+	return s == Style{}
+}
+
+// Merge returns a copy of s overridden by all the non-zero properties of o
+func (s Style) Merge(o Style) Style {
+	if !o.Fg.IsDefault() {
+		s.Fg = o.Fg
+	}
+	if !o.Bg.IsDefault() {
+		s.Bg = o.Bg
+	}
+	s.Bold = s.Bold || o.Bold
+	s.Dim = s.Dim || o.Dim
+	s.Italic = s.Italic || o.Italic
+	s.Underline = s.Underline || o.Underline
+	s.Reverse = s.Reverse || o.Reverse
+
+	return s
+}
+
+// Sprint wraps every line of text in ANSI SGR escape sequences supported
+// by the profile
+func (s Style) Sprint(text string, profile ColorProfile) string {
+
+	if s.IsZero() || text == "" {
+		return text
+	}
+
+	profile = resolveProfile(profile)
+	if profile == ProfileNone {
+		return text
+	}
+
+	// Collect SGR parameters
+	params := []string{}
+	for _, attr := range []struct {
+		on   bool
+		code string
+	}{
+		{s.Bold, "1"}, {s.Dim, "2"}, {s.Italic, "3"}, {s.Underline, "4"}, {s.Reverse, "7"},
+	} {
+		if attr.on {
+			params = append(params, attr.code)
+		}
+	}
+	if fg := s.Fg.sgr(profile, false); fg != "" {
+		params = append(params, fg)
+	}
+	if bg := s.Bg.sgr(profile, true); bg != "" {
+		params = append(params, bg)
+	}
+	if len(params) == 0 {
+		return text
+	}
+
+	// Style each line separately, so that borders are not affected
+	prefix := fmt.Sprintf("\033[%sm", strings.Join(params, ";"))
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line + "\033[0m"
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Theme styles the regions of a rendered table independently of the cell
+// modifiers. Styling is applied after the cell widths have been calculated,
+// so it never affects the layout of a table.
+type Theme struct {
+
+	// Profile limits the colors used by the theme. Colors are downgraded
+	// to the closest supported color. ProfileAuto detects the profile of
+	// os.Stdout (see DetectColorProfile).
+	Profile ColorProfile
+
+	Border   Style // Table borders and cell walls
+	Header   Style // Header cells
+	Footer   Style // Footer cells
+	Title    Style // Table titles
+	Footnote Style // Footnotes and their horizontal rule
+
+	// Rows is applied to odd and AltRows to even body rows (zebra stripes)
+	Rows, AltRows Style
+
+	// Columns contains default styles of body cells by the position of the
+	// rendered column (starting at 0). Column styles take precedence over
+	// row styles.
+	Columns map[int]Style
+}
+
+// DetectColorProfile returns the color profile of os.Stdout.
+//
+// Colors are disabled if the NO_COLOR environment variable is set or if
+// os.Stdout is not a terminal. Otherwise COLORTERM and TERM are used to
+// determine whether 24-bit or 256 colors are supported.
+func DetectColorProfile() ColorProfile {
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return ProfileNone
+	}
+
+	if !terminal.IsTerminal(int(os.Stdout.Fd())) {
+		return ProfileNone
+	}
+
+	return profileFromEnv(os.Getenv("COLORTERM"), os.Getenv("TERM"))
+}
+
+// profileFromEnv determines the color profile from the COLORTERM and TERM
+// environment variables
+func profileFromEnv(colorterm, term string) ColorProfile {
+
+	switch strings.ToLower(colorterm) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	term = strings.ToLower(term)
+	switch {
+	case term == "dumb":
+		return ProfileNone
+	case strings.Contains(term, "256color"):
+		return ProfileANSI256
+	default:
+		return ProfileANSI
+	}
+}
+
+// resolveProfile replaces ProfileAuto with the detected profile
+func resolveProfile(profile ColorProfile) ColorProfile {
+	if profile == ProfileAuto {
+		return DetectColorProfile()
+	}
+	return profile
+}
+
+// painter styles the borders and cells of rendered lines
+type painter struct {
+	profile ColorProfile
+	border  Style
+	cell    func(col int) Style
+}
+
+// borders styles border characters
+func (p painter) borders(s string) string {
+	return p.border.Sprint(s, p.profile)
+}
+
+// cells styles the content of the col-th cell
+func (p painter) cells(col int, s string) string {
+	if p.cell == nil {
+		return s
+	}
+	return p.cell(col).Sprint(s, p.profile)
+}
+
+// stripANSI removes ANSI escape sequences from a string
+func stripANSI(s string) string {
+
+	if !strings.Contains(s, "\033[") {
+		return s
+	}
+
+	stripped := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\033' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j
+			continue
+		}
+		stripped = append(stripped, s[i])
+	}
+
+	return string(stripped)
+}
+
+// visibleLength returns the count of printable runes in a string
+func visibleLength(s string) int {
+	return utf8.RuneCountInString(stripANSI(s))
+}
+
+// ansiColors contains the RGB values of the 16 basic ANSI colors (xterm)
+var ansiColors = [16]uint32{
+	0x000000, 0xcd0000, 0x00cd00, 0xcdcd00, 0x0000ee, 0xcd00cd, 0x00cdcd, 0xe5e5e5,
+	0x7f7f7f, 0xff0000, 0x00ff00, 0xffff00, 0x5c5cff, 0xff00ff, 0x00ffff, 0xffffff,
+}
+
+// cubeLevels are the intensities of the 6x6x6 xterm color cube
+var cubeLevels = [6]uint32{0, 95, 135, 175, 215, 255}
+
+// xtermToRGB converts an xterm color to a 24-bit color
+func xtermToRGB(n int) uint32 {
+	switch {
+	case n < 16:
+		return ansiColors[n]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36]<<16 | cubeLevels[(n/6)%6]<<8 | cubeLevels[n%6]
+	default:
+		level := uint32(8 + (n-232)*10)
+		return level<<16 | level<<8 | level
+	}
+}
+
+// rgbTo256 returns the closest xterm color (cube or grayscale)
+func rgbTo256(rgb uint32) int {
+
+	closest, distance := 16, -1
+	for n := 16; n < 256; n++ {
+		if d := colorDistance(rgb, xtermToRGB(n)); distance == -1 || d < distance {
+			closest, distance = n, d
+		}
+	}
+
+	return closest
+}
+
+// nearestANSI returns the closest basic ANSI color
+func nearestANSI(rgb uint32) int {
+
+	closest, distance := 0, -1
+	for n, ansi := range ansiColors {
+		if d := colorDistance(rgb, ansi); distance == -1 || d < distance {
+			closest, distance = n, d
+		}
+	}
+
+	return closest
+}
+
+// colorDistance returns the squared euclidean distance between two colors
+func colorDistance(a, b uint32) int {
+	dr := int(a>>16&0xff) - int(b>>16&0xff)
+	dg := int(a>>8&0xff) - int(b>>8&0xff)
+	db := int(a&0xff) - int(b&0xff)
+	return dr*dr + dg*dg + db*db
+}
+
+// clampInt limits n to [min, max]
+func clampInt(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
@@ -0,0 +1,97 @@
+package lentele
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStyleSprint(t *testing.T) {
+
+	tests := []struct {
+		style    Style
+		profile  ColorProfile
+		expected string
+	}{
+		{Style{}, ProfileTrueColor, "text"},
+		{Style{Fg: Red, Bold: true}, ProfileNone, "text"},
+		{Style{Fg: Red, Bold: true}, ProfileANSI, "\033[1;31mtext\033[0m"},
+		{Style{Fg: BrightRed, Bg: Blue}, ProfileANSI, "\033[91;44mtext\033[0m"},
+		{Style{Fg: XtermColor(196)}, ProfileANSI256, "\033[38;5;196mtext\033[0m"},
+		{Style{Fg: XtermColor(196)}, ProfileANSI, "\033[91mtext\033[0m"},
+		{Style{Bg: RGB(255, 0, 0)}, ProfileTrueColor, "\033[48;2;255;0;0mtext\033[0m"},
+		{Style{Bg: RGB(255, 0, 0)}, ProfileANSI256, "\033[48;5;196mtext\033[0m"},
+		{Style{Bg: RGB(255, 0, 0)}, ProfileANSI, "\033[101mtext\033[0m"},
+		{Style{Underline: true}, ProfileANSI, "\033[4mtext\033[0m"},
+	}
+
+	for i, test := range tests {
+		if styled := test.style.Sprint("text", test.profile); styled != test.expected {
+			t.Errorf("TestStyleSprint: test %d failed: expected %q, got %q", i+1, test.expected, styled)
+		}
+	}
+
+	if styled := (Style{Fg: Red}).Sprint("a\nb", ProfileANSI); styled != "\033[31ma\033[0m\n\033[31mb\033[0m" {
+		t.Errorf("TestStyleSprint: every line should be styled separately, got %q", styled)
+	}
+}
+
+func TestColorProfile(t *testing.T) {
+
+	os.Setenv("NO_COLOR", "1")
+	defer os.Unsetenv("NO_COLOR")
+
+	if profile := DetectColorProfile(); profile != ProfileNone {
+		t.Errorf("TestColorProfile: NO_COLOR should disable colors")
+	}
+
+	tests := []struct {
+		colorterm, term string
+		expected        ColorProfile
+	}{
+		{"truecolor", "xterm", ProfileTrueColor},
+		{"", "xterm-256color", ProfileANSI256},
+		{"", "xterm", ProfileANSI},
+		{"", "dumb", ProfileNone},
+	}
+
+	for i, test := range tests {
+		if profile := profileFromEnv(test.colorterm, test.term); profile != test.expected {
+			t.Errorf("TestColorProfile: test %d failed: expected %d, got %d", i+1, test.expected, profile)
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+
+	theme := Theme{
+		Profile:  ProfileTrueColor,
+		Border:   Style{Fg: BrightBlack},
+		Header:   Style{Bold: true},
+		Footer:   Style{Italic: true},
+		Title:    Style{Fg: Cyan},
+		Footnote: Style{Dim: true},
+		AltRows:  Style{Bg: XtermColor(236)},
+		Columns:  map[int]Style{2: {Fg: RGB(200, 100, 0)}},
+	}
+
+	for _, name := range builtinTemplates {
+		plain := bytes.NewBuffer([]byte{})
+		buildGDPTable(true, true, true).Render(plain, false, true, false, MustLoadTemplate(name))
+
+		tmpl := MustLoadTemplate(name)
+		tmpl.SetTheme(theme)
+
+		themed := bytes.NewBuffer([]byte{})
+		buildGDPTable(true, true, true).Render(themed, false, true, false, tmpl)
+
+		if !strings.Contains(themed.String(), "\033[") {
+			t.Errorf("TestTheme: template '%s' did not apply the theme", name)
+		}
+
+		if stripANSI(themed.String()) != plain.String() {
+			t.Errorf("TestTheme: theme should not change the layout of template '%s'", name)
+		}
+	}
+}
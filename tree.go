@@ -0,0 +1,278 @@
+package lentele
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Tree glyphs
+const (
+	treeBranch = "├─ "
+	treeLast   = "└─ "
+	treePipe   = "│  "
+	treeSpace  = "   "
+)
+
+// treeOptions describes how tree tables are rendered
+type treeOptions struct {
+	depth      int                  // Count of rendered levels (0 = unlimited)
+	aggregates map[string]Aggregate // Aggregates of child rows by column name
+}
+
+// treeLayout contains the rendering information of a tree table
+type treeLayout struct {
+	prefixes map[*row]string // Tree glyphs of body rows
+	hidden   map[*row]bool   // Rows deeper than the max depth
+
+	children map[*row][]*row
+}
+
+// AddChild adds a new row right after the last descendant of the row. The
+// child is rendered with tree glyphs in the first column. Children of the
+// header or the footer are regular rows.
+// NB: locks the parent table
+func (r *row) AddChild(name string) Row {
+	t := r.tref
+	t.Lock()
+	defer t.Unlock()
+
+	name, err := t.uniqueName(strings.ToLower(name))
+
+	child := &row{
+		Mutex:  &sync.Mutex{},
+		Cells:  []*cell{},
+		tref:   t,
+		parent: r,
+	}
+	if err != nil && t.Strict {
+		child.err = fmt.Errorf("AddChild: %s", err.Error())
+	}
+
+	if r == t.headAndFoot["header"] || r == t.headAndFoot["footer"] {
+		child.parent = nil
+	}
+
+	// Position of the last descendant
+	position := -1
+	for i, candidate := range t.Rows {
+		if candidate == r || (position != -1 && isDescendant(candidate, r)) {
+			position = i
+		}
+	}
+
+	t.restructure()
+	if position == -1 || child.parent == nil {
+		t.Rows = append(t.Rows, child)
+		t.RowNames = append(t.RowNames, name)
+		return child
+	}
+
+	t.Rows = append(t.Rows[:position+1], append([]*row{child}, t.Rows[position+1:]...)...)
+	t.RowNames = append(t.RowNames[:position+1], append([]string{name}, t.RowNames[position+1:]...)...)
+
+	return child
+}
+
+// isDescendant returns true if r is a (grand)child of ancestor
+func isDescendant(r, ancestor *row) bool {
+	for parent := r.parent; parent != nil; parent = parent.parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTreeDepth limits the count of rendered levels of tree tables, i.e. rows
+// nested deeper are collapsed into their ancestors. Depth 0 renders all the
+// rows, depth 1 only the top-level rows, depth 2 also their children and so on.
+// NB: locks t
+func (t *table) SetTreeDepth(depth int) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if depth < 0 {
+		return fmt.Errorf("SetTreeDepth: depth cannot be negative")
+	}
+
+	t.tree.depth = depth
+
+	return nil
+}
+
+// SetTreeAggregates replaces the values of parent rows with the aggregates
+// (by column name) of the values of their leaf descendants at render time
+// NB: locks t
+func (t *table) SetTreeAggregates(aggregates map[string]Aggregate) error {
+	t.Lock()
+	defer t.Unlock()
+
+	for colname, aggregate := range aggregates {
+		if len(t.getColIdx(colname)) == 0 {
+			return fmt.Errorf("SetTreeAggregates: no such column '%s'", colname)
+		}
+		if aggregate == nil {
+			return fmt.Errorf("SetTreeAggregates: aggregate of column '%s' cannot be nil", colname)
+		}
+	}
+
+	t.tree.aggregates = aggregates
+
+	return nil
+}
+
+// FilterTree is same as Filter, only keeps the ancestors of the matching
+// rows, so that the tree structure is preserved
+// NB: locks t
+func (t *table) FilterTree(filter func(values ...interface{}) bool, inplace, keepFooter bool, columns ...string) (Table, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	fTable, err := t.filter(filter, inplace, keepFooter, true, columns...)
+	if err != nil {
+		return nil, fmt.Errorf("FilterTree: %s", err.Error())
+	}
+
+	return fTable, nil
+}
+
+// layoutTree calculates the tree glyphs and the collapsed rows of body rows.
+// Rows whose parents are not part of the table are top-level rows.
+// Returns nil if the table has no tree.
+func (t *table) layoutTree() *treeLayout {
+
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	present := map[*row]bool{}
+	for _, row := range t.Rows {
+		present[row] = true
+	}
+
+	layout := &treeLayout{
+		prefixes: map[*row]string{},
+		hidden:   map[*row]bool{},
+		children: map[*row][]*row{},
+	}
+
+	roots := []*row{}
+	isTree := false
+	for _, row := range t.Rows {
+		if row == header || row == footer {
+			continue
+		}
+		if row.parent != nil && present[row.parent] {
+			layout.children[row.parent] = append(layout.children[row.parent], row)
+			isTree = true
+		} else {
+			roots = append(roots, row)
+		}
+	}
+
+	if !isTree {
+		return nil
+	}
+
+	// Walk the tree depth-first
+	var walk func(rows []*row, indent string, depth int)
+	walk = func(rows []*row, indent string, depth int) {
+		for k, row := range rows {
+			last := k == len(rows)-1
+
+			glyph, childIndent := treeBranch, indent+treePipe
+			if last {
+				glyph, childIndent = treeLast, indent+treeSpace
+			}
+
+			layout.prefixes[row] = indent + glyph
+			layout.hidden[row] = t.tree.depth > 0 && depth >= t.tree.depth
+			walk(layout.children[row], childIndent, depth+1)
+		}
+	}
+
+	for _, root := range roots {
+		layout.prefixes[root] = ""
+		walk(layout.children[root], "", 1)
+	}
+
+	return layout
+}
+
+// prefix returns the tree glyphs of the row's j-th rendered column
+func (l *treeLayout) prefix(r *row, j int) string {
+	if l == nil || j != 0 {
+		return ""
+	}
+	return l.prefixes[r]
+}
+
+// aggregate returns the aggregated value of a parent row's column
+func (l *treeLayout) aggregate(r *row, col int, aggregate Aggregate) (interface{}, bool) {
+
+	if l == nil || aggregate == nil || len(l.children[r]) == 0 {
+		return nil, false
+	}
+
+	// Collect the values of leaf descendants
+	values := []interface{}{}
+	var collect func(rows []*row)
+	collect = func(rows []*row) {
+		for _, row := range rows {
+			if children := l.children[row]; len(children) > 0 {
+				collect(children)
+			} else if col < len(row.Cells) {
+				values = append(values, row.Cells[col].Value)
+			}
+		}
+	}
+	collect(l.children[r])
+
+	return aggregate(values), true
+}
+
+// treeAggregates returns the aggregates of the tree by column index
+func (t *table) treeAggregates() map[int]Aggregate {
+	aggregates := map[int]Aggregate{}
+	for colname, aggregate := range t.tree.aggregates {
+		if idx := t.getColIdx(colname); len(idx) > 0 {
+			aggregates[idx[0]] = aggregate
+		}
+	}
+	return aggregates
+}
+
+// parentIndices stores the positions of parent rows for marshaling
+func (t *table) parentIndices() {
+	index := map[*row]int{}
+	for i, row := range t.Rows {
+		index[row] = i
+	}
+	for _, row := range t.Rows {
+		row.Parent = nil
+		if i, ok := index[row.parent]; ok && row.parent != nil {
+			parent := i
+			row.Parent = &parent
+		}
+	}
+}
+
+// restoreParents restores the parent references of unmarshaled rows
+func (t *table) restoreParents() {
+	for _, row := range t.Rows {
+		if row.Parent != nil && *row.Parent >= 0 && *row.Parent < len(t.Rows) && t.Rows[*row.Parent] != row {
+			row.parent = t.Rows[*row.Parent]
+		}
+	}
+}
+
+// padLines pads every line of s with spaces up to width
+func padLines(s string, width int) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if length := visibleLength(line); length < width {
+			lines[i] = line + strings.Repeat(" ", width-length)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
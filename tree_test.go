@@ -0,0 +1,98 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// buildTreeTable builds a small directory tree
+func buildTreeTable() Table {
+	table := New("Name", "Size")
+	root := table.AddRow("root").Insert("/", 0)
+	etc := root.AddChild("").Insert("etc", 0)
+	usr := root.AddChild("").Insert("usr", 0)
+	etc.AddChild("").Insert("hosts", 1)
+	usr.AddChild("").Insert("bin", 0).AddChild("").Insert("ls", 10)
+	etc.AddChild("").Insert("passwd", 2)
+	table.AddRow("").Insert("swap", 5)
+
+	return table
+}
+
+func TestTree(t *testing.T) {
+
+	table := buildTreeTable()
+
+	expected := []string{
+		"| /            |  0   |",
+		"| ├─ etc       |  0   |",
+		"| │  ├─ hosts  |  1   |",
+		"| │  └─ passwd |  2   |",
+		"| └─ usr       |  0   |",
+		"|    └─ bin    |  0   |",
+		"|       └─ ls  |  10  |",
+		"| swap         |  5   |",
+	}
+	if rendered := renderMySQL(table); !strings.Contains(rendered, strings.Join(expected, "\n")) {
+		t.Errorf("TestTree: unexpected output:\n%s", rendered)
+	}
+
+	// Collapsed levels and aggregates
+	if err := table.SetTreeDepth(-1); err == nil {
+		t.Errorf("TestTree: negative depths should fail")
+	}
+	if err := table.SetTreeAggregates(map[string]Aggregate{"Owner": Sum}); err == nil {
+		t.Errorf("TestTree: unknown columns should fail")
+	}
+	table.SetTreeDepth(2)
+	table.SetTreeAggregates(map[string]Aggregate{"Size": Sum})
+
+	rendered := renderMySQL(table)
+	for _, line := range []string{"| /      |  13  |", "| ├─ etc |  3   |"} {
+		if !strings.Contains(rendered, line) {
+			t.Errorf("TestTree: line '%s' is missing:\n%s", line, rendered)
+		}
+	}
+	if strings.Contains(rendered, "hosts") {
+		t.Errorf("TestTree: collapsed rows should not be rendered:\n%s", rendered)
+	}
+}
+
+func TestFilterTree(t *testing.T) {
+
+	table := buildTreeTable()
+
+	filtered, err := table.FilterTree(func(values ...interface{}) bool { return values[0] == "ls" }, false, false, "Name")
+	if err != nil {
+		t.Fatalf("TestFilterTree: could not filter table: %s", err.Error())
+	}
+
+	// Ancestors of matching rows are kept
+	checkValues(t, "TestFilterTree", filtered, "Name", "/", "usr", "bin", "ls")
+
+	// Regular filters do not keep ancestors
+	filtered, _ = table.Filter(func(values ...interface{}) bool { return values[0] == "ls" }, false, false, "Name")
+	if rendered := renderMySQL(filtered); !strings.Contains(rendered, "|  ls  |  10  |") {
+		t.Errorf("TestFilterTree: orphans should be top-level rows:\n%s", rendered)
+	}
+}
+
+func TestTreeJSON(t *testing.T) {
+
+	table := buildTreeTable()
+
+	jsoned := bytes.NewBuffer([]byte{})
+	if _, err := table.MarshalToRichJSON(jsoned); err != nil {
+		t.Fatalf("TestTreeJSON: could not marshal table: %s", err.Error())
+	}
+
+	restored, err := NewFromRichJSON(jsoned)
+	if err != nil {
+		t.Fatalf("TestTreeJSON: could not unmarshal table: %s", err.Error())
+	}
+
+	if renderMySQL(restored) != renderMySQL(table) {
+		t.Errorf("TestTreeJSON: the tree should survive a round trip:\n%s", renderMySQL(restored))
+	}
+}
@@ -0,0 +1,182 @@
+package lentele
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TypeFormat formats (and styles) the values matched by a predicate, e.g. all
+// float64s, errors or nils. Type formats are only consulted for columns
+// without a format (Table.SetFormat) or a formatter (Table.SetFormatter).
+type TypeFormat struct {
+	Match     func(v interface{}) bool // Values to format
+	Formatter Formatter                // Formatter of the values ("%v" if nil)
+	Style     Style                    // Style of the values (modified view only)
+}
+
+// typeFormats contains the type formats shared by all tables
+var typeFormats = &typeFormatRegistry{
+	Mutex: &sync.Mutex{},
+}
+
+// typeFormatRegistry contains type formats, the latest first
+type typeFormatRegistry struct {
+	*sync.Mutex
+	formats []*TypeFormat
+}
+
+// RegisterTypeFormat adds a type format shared by all tables. Type formats of
+// tables (Table.AddTypeFormat) take precedence over shared ones and formats
+// registered later take precedence over earlier ones.
+func RegisterTypeFormat(format *TypeFormat) error {
+	typeFormats.Lock()
+	defer typeFormats.Unlock()
+
+	if format == nil || format.Match == nil {
+		return fmt.Errorf("RegisterTypeFormat: type format must have a predicate")
+	}
+
+	typeFormats.formats = append([]*TypeFormat{format}, typeFormats.formats...)
+
+	return nil
+}
+
+// ResetTypeFormats removes all the type formats shared by all tables
+func ResetTypeFormats() {
+	typeFormats.Lock()
+	defer typeFormats.Unlock()
+
+	typeFormats.formats = nil
+}
+
+// match returns the first type format matching the value
+func (r *typeFormatRegistry) match(v interface{}) *TypeFormat {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, format := range r.formats {
+		if format.Match(v) {
+			return format
+		}
+	}
+	return nil
+}
+
+// MatchType returns a predicate matching the values of the same type as the
+// sample, e.g. MatchType(0.0) matches all float64s
+func MatchType(sample interface{}) func(v interface{}) bool {
+	sampleType := reflect.TypeOf(sample)
+	return func(v interface{}) bool {
+		return reflect.TypeOf(v) == sampleType
+	}
+}
+
+// MatchNil matches nil values
+func MatchNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return value.IsNil()
+	}
+	return false
+}
+
+// MatchError matches values implementing the error interface
+func MatchError(v interface{}) bool {
+	_, ok := v.(error)
+	return ok
+}
+
+// PrintfFormatter formats all values with a printf format
+func PrintfFormatter(format string) Formatter {
+	return func(v interface{}) (string, bool) {
+		formatted := fmt.Sprintf(format, v)
+		if strings.Contains(formatted, "%!") {
+			return "", false
+		}
+		return formatted, true
+	}
+}
+
+// AddTypeFormat adds a type format to the table. Formats added later take
+// precedence over earlier ones.
+// NB: locks t
+func (t *table) AddTypeFormat(format *TypeFormat) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if format == nil || format.Match == nil {
+		return fmt.Errorf("AddTypeFormat: type format must have a predicate")
+	}
+
+	t.typeFormats = append([]*TypeFormat{format}, t.typeFormats...)
+
+	return nil
+}
+
+// ClearTypeFormats removes the type formats of the table
+// NB: locks t
+func (t *table) ClearTypeFormats() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.typeFormats = nil
+}
+
+// typeFormat returns the type format of a value (table formats first)
+func (t *table) typeFormat(v interface{}) *TypeFormat {
+	for _, format := range t.typeFormats {
+		if format.Match(v) {
+			return format
+		}
+	}
+	return typeFormats.match(v)
+}
+
+// formatValue formats a value with the column's formatter (col is the index
+// of the column, pos its rendered position) or, if the column has neither a
+// formatter nor a format, its type format. Returns false if the value has to
+// be formatted with the column's format.
+func (t *table) formatValue(col, pos int, value interface{}) (string, Style, bool) {
+
+	format, hasFormat := t.Formats[pos]
+	if !hasFormat {
+		format = "%v"
+	}
+
+	// Column formatters
+	if formatter, ok := t.formatters[col]; ok {
+		if formatted, ok := formatter(value); ok {
+
+			// Formats can still pad the formatted values
+			if padded := fmt.Sprintf(format, formatted); !strings.Contains(padded, "%!") {
+				return padded, Style{}, true
+			}
+			return formatted, Style{}, true
+		}
+	}
+
+	if hasFormat {
+		return "", Style{}, false
+	}
+
+	// Type formats
+	typeFormat := t.typeFormat(value)
+	if typeFormat == nil {
+		return "", Style{}, false
+	}
+
+	if typeFormat.Formatter == nil {
+		return fmt.Sprintf("%v", value), typeFormat.Style, true
+	}
+	if formatted, ok := typeFormat.Formatter(value); ok {
+		return formatted, typeFormat.Style, true
+	}
+
+	return "", Style{}, false
+}
@@ -0,0 +1,54 @@
+package lentele
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTypeFormats(t *testing.T) {
+
+	hosts := New("Host", "Load", "Error")
+	hosts.AddRow("").Insert("alpha", 0.51234, nil)
+	hosts.AddRow("").Insert("beta", 1.5, errors.New("timeout"))
+
+	if err := hosts.AddTypeFormat(&TypeFormat{}); err == nil {
+		t.Errorf("TestTypeFormats: type formats without predicates should fail")
+	}
+
+	// Shared type formats
+	if err := RegisterTypeFormat(&TypeFormat{Match: MatchType(0.0), Formatter: PrintfFormatter("%.1f")}); err != nil {
+		t.Fatalf("TestTypeFormats: could not register type format: %s", err.Error())
+	}
+	defer ResetTypeFormats()
+
+	// Table type formats take precedence
+	hosts.AddTypeFormat(&TypeFormat{Match: MatchNil, Formatter: func(v interface{}) (string, bool) { return "—", true }})
+	hosts.AddTypeFormat(&TypeFormat{Match: MatchError, Style: Style{Fg: Red}})
+
+	out := bytes.NewBuffer([]byte{})
+	hosts.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	rendered := out.String()
+	if !strings.Contains(rendered, "| alpha | 0.5  |    —    |\n| beta  | 1.5  | timeout |") {
+		t.Errorf("TestTypeFormats: unexpected output:\n%s", rendered)
+	}
+
+	// Column formats take precedence over type formats
+	hosts.SetFormat("%.3f", "Load")
+	out.Reset()
+	hosts.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if !strings.Contains(out.String(), "0.512") {
+		t.Errorf("TestTypeFormats: column formats should take precedence:\n%s", out.String())
+	}
+
+	// Rendering does not set formats
+	if _, ok := hosts.(*table).Formats[2]; ok {
+		t.Errorf("TestTypeFormats: rendering should not set formats")
+	}
+
+	hosts.ClearTypeFormats()
+	if typeFormat := hosts.(*table).typeFormat(nil); typeFormat != nil {
+		t.Errorf("TestTypeFormats: type formats should be cleared")
+	}
+}
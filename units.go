@@ -0,0 +1,201 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units of byte sizes
+var (
+	iecUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// Units of compact durations (largest first)
+var durationUnits = []struct {
+	symbol string
+	size   time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+	{"ms", time.Millisecond},
+	{"µs", time.Microsecond},
+	{"ns", time.Nanosecond},
+}
+
+// Units of relative times (largest first)
+var relativeUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// BytesFormatter formats numeric byte counts as IEC (KiB, MiB, ...) or, if si
+// is set, SI (kB, MB, ...) sizes, e.g. "1.5 GiB"
+func BytesFormatter(si bool, decimals int) Formatter {
+
+	base, units := 1024.0, iecUnits
+	if si {
+		base, units = 1000.0, siUnits
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	return func(v interface{}) (string, bool) {
+		size, ok := toFloat(v)
+		if !ok || math.IsNaN(size) || math.IsInf(size, 0) {
+			return "", false
+		}
+
+		sign := ""
+		if size < 0 {
+			sign, size = "-", -size
+		}
+
+		if size < base {
+			return fmt.Sprintf("%s%.0f %s", sign, size, units[0]), true
+		}
+
+		unit := 0
+		for size >= base && unit < len(units)-1 {
+			size /= base
+			unit++
+		}
+
+		// Rounding may reach the next unit (e.g. 1023.99 KiB)
+		if rounded, _ := strconv.ParseFloat(strconv.FormatFloat(size, 'f', decimals, 64), 64); rounded >= base && unit < len(units)-1 {
+			size /= base
+			unit++
+		}
+
+		return fmt.Sprintf("%s%s %s", sign, strconv.FormatFloat(size, 'f', decimals, 64), units[unit]), true
+	}
+}
+
+// DurationFormatter formats time.Durations compactly using the largest units,
+// e.g. "3h12m" (parts = 2) or "3h12m5s" (parts = 3)
+func DurationFormatter(parts int) Formatter {
+
+	if parts < 1 {
+		parts = 1
+	}
+
+	return func(v interface{}) (string, bool) {
+		duration, ok := v.(time.Duration)
+		if !ok {
+			return "", false
+		}
+
+		if duration == 0 {
+			return "0s", true
+		}
+
+		sign := ""
+		if duration < 0 {
+			sign, duration = "-", -duration
+		}
+
+		// Units skipped after the largest unit count as parts
+		compact := []string{}
+		used := 0
+		for _, unit := range durationUnits {
+			if used == parts {
+				break
+			}
+			if count := duration / unit.size; count > 0 || used > 0 {
+				if count > 0 {
+					compact = append(compact, fmt.Sprintf("%d%s", count, unit.symbol))
+				}
+				duration -= count * unit.size
+				used++
+			}
+		}
+
+		return sign + strings.Join(compact, ""), true
+	}
+}
+
+// TimeFormatter formats time.Times with the layout (see time.Format) in the
+// location. Times keep their own location if location is nil.
+func TimeFormatter(layout string, location *time.Location) Formatter {
+
+	return func(v interface{}) (string, bool) {
+		timestamp, ok := toTime(v)
+		if !ok {
+			return "", false
+		}
+
+		if location != nil {
+			timestamp = timestamp.In(location)
+		}
+
+		return timestamp.Format(layout), true
+	}
+}
+
+// RelativeTimeFormatter formats time.Times relative to the current time of
+// the clock, e.g. "5 minutes ago" or "in 2 days". The clock defaults to
+// time.Now if nil.
+func RelativeTimeFormatter(clock func() time.Time) Formatter {
+
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return func(v interface{}) (string, bool) {
+		timestamp, ok := toTime(v)
+		if !ok {
+			return "", false
+		}
+
+		elapsed := clock().Sub(timestamp)
+		future := elapsed < 0
+		if future {
+			elapsed = -elapsed
+		}
+
+		for _, unit := range relativeUnits {
+			count := int64(elapsed / unit.size)
+			if count == 0 {
+				continue
+			}
+
+			relative := fmt.Sprintf("%d %s", count, unit.name)
+			if count > 1 {
+				relative += "s"
+			}
+			if future {
+				return "in " + relative, true
+			}
+			return relative + " ago", true
+		}
+
+		return "just now", true
+	}
+}
+
+// toTime converts time.Times and their pointers to time.Time
+func toTime(v interface{}) (time.Time, bool) {
+	switch value := v.(type) {
+	case time.Time:
+		return value, true
+	case *time.Time:
+		if value != nil {
+			return *value, true
+		}
+	}
+	return time.Time{}, false
+}
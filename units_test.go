@@ -0,0 +1,71 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnitFormatters(t *testing.T) {
+
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	vilnius := time.FixedZone("EET", 2*60*60)
+
+	cases := []struct {
+		formatter Formatter
+		value     interface{}
+		expected  string
+	}{
+		{BytesFormatter(false, 1), 512, "512 B"},
+		{BytesFormatter(false, 1), 1536, "1.5 KiB"},
+		{BytesFormatter(false, 1), int64(5) << 40, "5.0 TiB"},
+		{BytesFormatter(false, 0), 1048575, "1 MiB"},
+		{BytesFormatter(true, 2), 1234567, "1.23 MB"},
+		{BytesFormatter(true, 0), -2000, "-2 kB"},
+		{DurationFormatter(2), 3*time.Hour + 12*time.Minute + 5*time.Second, "3h12m"},
+		{DurationFormatter(3), 3*time.Hour + 12*time.Minute + 5*time.Second, "3h12m5s"},
+		{DurationFormatter(2), 3*time.Hour + 5*time.Second, "3h"},
+		{DurationFormatter(2), 1500 * time.Millisecond, "1s500ms"},
+		{DurationFormatter(1), -26 * time.Hour, "-1d"},
+		{DurationFormatter(2), time.Duration(0), "0s"},
+		{TimeFormatter("2006-01-02 15:04 MST", nil), now, "2024-03-15 12:00 UTC"},
+		{TimeFormatter("2006-01-02 15:04 MST", vilnius), now, "2024-03-15 14:00 EET"},
+		{RelativeTimeFormatter(clock), now.Add(-5 * time.Minute), "5 minutes ago"},
+		{RelativeTimeFormatter(clock), now.Add(-time.Hour), "1 hour ago"},
+		{RelativeTimeFormatter(clock), now.Add(50 * time.Hour), "in 2 days"},
+		{RelativeTimeFormatter(clock), now.Add(-400 * 24 * time.Hour), "1 year ago"},
+		{RelativeTimeFormatter(clock), now, "just now"},
+	}
+
+	for _, c := range cases {
+		if formatted, ok := c.formatter(c.value); !ok || formatted != c.expected {
+			t.Errorf("TestUnitFormatters: expected '%s', got '%s' (%v)", c.expected, formatted, c.value)
+		}
+	}
+
+	if _, ok := DurationFormatter(2)(3600); ok {
+		t.Errorf("TestUnitFormatters: only durations should be formatted as durations")
+	}
+	if _, ok := TimeFormatter(time.RFC3339, nil)("2024-03-15"); ok {
+		t.Errorf("TestUnitFormatters: only times should be formatted as times")
+	}
+}
+
+func TestUnitColumns(t *testing.T) {
+
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	table := New("Pod", "Memory", "Uptime", "Restarted")
+	table.AddRow("").Insert("api", 268435456, 75*time.Hour, now.Add(-90*time.Second))
+	table.SetFormatter(BytesFormatter(false, 0), "Memory")
+	table.SetFormatter(DurationFormatter(2), "Uptime")
+	table.SetFormatter(RelativeTimeFormatter(func() time.Time { return now }), "Restarted")
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if rendered := out.String(); !strings.Contains(rendered, "| api | 256 MiB |  3d3h  | 1 minute ago |") {
+		t.Errorf("TestUnitColumns: unexpected output:\n%s", rendered)
+	}
+}
@@ -0,0 +1,283 @@
+package lentele
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves the current and the incoming values of a column of rows
+// with matching keys (see Table.MergeFrom)
+type Resolver func(column string, current, incoming interface{}) interface{}
+
+// MergeOptions configures Table.MergeFrom
+type MergeOptions struct {
+	Resolve        Resolver // Resolves the values of matching rows (default: PreferIncoming)
+	ResetModifiers bool     // Removes the modifiers (Row.Modify) of updated cells
+}
+
+// PreferIncoming replaces current values with the incoming values
+func PreferIncoming(column string, current, incoming interface{}) interface{} {
+	return incoming
+}
+
+// PreferCurrent keeps current values, unless they are missing
+func PreferCurrent(column string, current, incoming interface{}) interface{} {
+	if isMissing(current) {
+		return incoming
+	}
+	return current
+}
+
+// Combine aggregates the current and the incoming values, e.g. Combine(Sum)
+// adds up counters. Missing values are skipped.
+func Combine(aggregate Aggregate) Resolver {
+	return func(column string, current, incoming interface{}) interface{} {
+		values := []interface{}{}
+		for _, v := range []interface{}{current, incoming} {
+			if !isMissing(v) {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return Missing
+		}
+		return aggregate(values)
+	}
+}
+
+// Upsert updates the (first) body row whose value of the key column is
+// (deeply) equal to the key value among the values or, if there is no such
+// row, appends a new row. The values are inserted in the order of the columns
+// (same as Row.Insert). Modifiers of the updated cells are kept. Rows are
+// looked up using an index of the key column.
+// NB: locks t, then the updated row
+func (t *table) Upsert(keyCol string, values ...interface{}) (Row, error) {
+
+	upserted, update, err := t.upsertValues(keyCol, values...)
+	if err != nil {
+		return nil, fmt.Errorf("Upsert: %s", err.Error())
+	}
+	if update != nil {
+		update.apply(MergeOptions{})
+	}
+
+	return upserted, nil
+}
+
+// upsertValues upserts the values (see upsert)
+// NB: locks t
+func (t *table) upsertValues(keyCol string, values ...interface{}) (*row, *upsertUpdate, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.headAndFoot["header"]; !ok {
+		return nil, nil, fmt.Errorf("table has no header")
+	}
+
+	col := t.getColnameIndex(keyCol, false, false)
+	if col == -1 {
+		return nil, nil, fmt.Errorf("no such column '%s'", keyCol)
+	}
+	if col >= len(values) {
+		return nil, nil, fmt.Errorf("no value of the key column '%s'", keyCol)
+	}
+
+	cells := map[int]interface{}{}
+	for j, v := range values {
+		cells[j] = v
+	}
+
+	// New rows are not named, i.e. there are no name conflicts
+	upserted, update, _ := t.upsert([]int{col}, cells, "")
+
+	return upserted, update, nil
+}
+
+// MergeFrom upserts the body rows of another table (see Upsert), matching the
+// rows by the values of the key columns. The columns are aligned by their
+// names: columns absent in t are added, columns absent in the other table are
+// not updated. The values of matching rows are resolved by the options'
+// resolver. New rows keep their names, unless the names are reserved or, in
+// strict mode, already used: such rows are merged without a name and the
+// first conflict is returned after the merge.
+// NB: locks other, then t (and the header and the updated rows)
+func (t *table) MergeFrom(other Table, options MergeOptions, keyCols ...string) error {
+
+	if len(keyCols) == 0 {
+		return fmt.Errorf("MergeFrom: provide at least one key column")
+	}
+
+	source, ok := other.(*table)
+	if !ok {
+		return fmt.Errorf("MergeFrom: unsupported table implementation")
+	}
+
+	snapshot, err := source.snapshot()
+	if err != nil {
+		return fmt.Errorf("MergeFrom: %s", err.Error())
+	}
+
+	colIdx, position, writes, err := t.mergeColumns(snapshot, keyCols...)
+	if err != nil {
+		return fmt.Errorf("MergeFrom: %s", err.Error())
+	}
+	writeCells(writes)
+
+	// Matching rows are updated after unlocking t
+	var conflict error
+	for i, incoming := range snapshot.rows {
+		cells := map[int]interface{}{}
+		for j, col := range position {
+			cells[col] = cellValue(incoming, j)
+		}
+
+		t.Lock()
+		_, update, err := t.upsert(colIdx, cells, snapshot.names[i])
+		t.Unlock()
+
+		if update != nil {
+			update.apply(options)
+		}
+		if err != nil && conflict == nil {
+			conflict = err
+		}
+	}
+
+	if conflict != nil {
+		return fmt.Errorf("MergeFrom: %s", conflict.Error())
+	}
+
+	return nil
+}
+
+// mergeColumns returns the positions of the key columns and aligns the columns
+// of the merged snapshot (see alignColumns)
+// NB: locks t
+func (t *table) mergeColumns(snapshot *appended, keyCols ...string) ([]int, []int, []cellWrite, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.headAndFoot["header"]; !ok {
+		return nil, nil, nil, fmt.Errorf("table has no header")
+	}
+
+	// Key columns must exist in both tables
+	colIdx := []int{}
+	for _, keyCol := range keyCols {
+		col := t.getColnameIndex(keyCol, false, false)
+		found := false
+		for _, colname := range snapshot.columns {
+			found = found || strings.EqualFold(colname, keyCol)
+		}
+		if col == -1 || !found {
+			return nil, nil, nil, fmt.Errorf("no such key column '%s'", keyCol)
+		}
+		colIdx = append(colIdx, col)
+	}
+
+	position, writes, err := t.alignColumns(snapshot.columns)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return colIdx, position, writes, nil
+}
+
+// upsertUpdate is a pending update of a body row matching an upserted row
+type upsertUpdate struct {
+	current *row                // Matching row
+	cells   map[int]interface{} // Incoming values by column position (except for the keys)
+	columns map[int]string      // Names of the updated columns
+}
+
+// upsert appends a new row (name) of the cells (by column position), unless
+// a body row matches the key columns' values of the cells. The matching row
+// is returned along with its update, which has to be applied after unlocking
+// t, since the row methods lock the row before the table. New rows with
+// conflicting names are not named (see uniqueName) and the conflict is
+// returned.
+func (t *table) upsert(colIdx []int, cells map[int]interface{}, name string) (*row, *upsertUpdate, error) {
+
+	keys := make([]interface{}, len(colIdx))
+	isKey := map[int]bool{}
+	for k, col := range colIdx {
+		keys[k] = Missing
+		if v, ok := cells[col]; ok {
+			keys[k] = v
+		}
+		isKey[col] = true
+	}
+
+	// Rows of equally printed keys (e.g. 1 and "1") are not matches
+	var current *row
+	for _, r := range t.keyIndex(colIdx).rows[indexKey(keys...)] {
+		if reflect.DeepEqual(rowValues(r, colIdx), keys) {
+			current = r
+			break
+		}
+	}
+
+	// Append a new row
+	if current == nil {
+		newRow := &row{Mutex: &sync.Mutex{}, Cells: []*cell{}, tref: t}
+		for col, v := range cells {
+			for len(newRow.Cells) <= col {
+				newRow.Cells = append(newRow.Cells, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+			}
+			newRow.Cells[col].Value = v
+		}
+		name, err := t.uniqueName(name)
+		if err != nil && t.Strict {
+			newRow.err = fmt.Errorf("Upsert: %s", err.Error())
+		}
+		t.Rows = append(t.Rows, newRow)
+		t.RowNames = append(t.RowNames, name)
+		t.indexName(newRow, name)
+		t.reindex(newRow)
+		return newRow, nil, err
+	}
+
+	// Update the matching row (except for the keys)
+	header := t.headAndFoot["header"]
+	pending := &upsertUpdate{current: current, cells: map[int]interface{}{}, columns: map[int]string{}}
+	for col, v := range cells {
+		if !isKey[col] {
+			pending.cells[col] = v
+			pending.columns[col] = fmt.Sprintf("%v", cellValue(header, col))
+		}
+	}
+
+	return current, pending, nil
+}
+
+// apply resolves the current values of the matching row with the incoming
+// values. The row's table must not be locked by the caller.
+// NB: locks the row, then its table
+func (u *upsertUpdate) apply(options MergeOptions) {
+
+	resolve := options.Resolve
+	if resolve == nil {
+		resolve = PreferIncoming
+	}
+
+	current := u.current
+	current.Lock()
+	defer current.Unlock()
+
+	for col, v := range u.cells {
+		for len(current.Cells) <= col {
+			current.Cells = append(current.Cells, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+		}
+
+		rcell := current.Cells[col]
+		rcell.Lock()
+		rcell.Value = resolve(u.columns[col], rcell.Value, v)
+		if options.ResetModifiers {
+			rcell.modFunc = nil
+		}
+		rcell.Unlock()
+	}
+	current.changed()
+}
@@ -0,0 +1,140 @@
+package lentele
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpsert(t *testing.T) {
+
+	table := New("Host", "Status", "Load")
+	table.AddRow("").Insert("web1", "up", 1).Modify(func(v interface{}) interface{} {
+		return strings.ToUpper(v.(string))
+	}, "Status")
+	table.AddRow("").Insert("web2", "up", 2)
+
+	if _, err := table.Upsert("Region", "web1"); err == nil {
+		t.Errorf("TestUpsert: unknown key columns should fail")
+	}
+	if _, err := table.Upsert("Load", "web1"); err == nil {
+		t.Errorf("TestUpsert: missing key values should fail")
+	}
+
+	// Update (keeping the modifier) and insert
+	if _, err := table.Upsert("Host", "web1", "down", 0); err != nil {
+		t.Fatalf("TestUpsert: could not upsert: %s", err.Error())
+	}
+	if _, err := table.Upsert("Host", "web3", "up", 3); err != nil {
+		t.Fatalf("TestUpsert: could not upsert: %s", err.Error())
+	}
+
+	// Changes made outside of Upsert invalidate the index
+	web2, _ := table.GetRow(2)
+	web2.Change("Host", "web4")
+	if _, err := table.Upsert("Host", "web4", "down"); err != nil {
+		t.Fatalf("TestUpsert: could not upsert: %s", err.Error())
+	}
+
+	checkValues(t, "TestUpsert", table, "Host", "web1", "web4", "web3")
+	checkValues(t, "TestUpsert", table, "Status", "down", "down", "up")
+	checkValues(t, "TestUpsert", table, "Load", 0, 2, 3)
+	if out := renderMySQL(table); !strings.Contains(out, "| web1 |  DOWN  |  0   |") {
+		t.Errorf("TestUpsert: the modifier should be kept:\n%s", out)
+	}
+}
+
+func TestMergeFrom(t *testing.T) {
+
+	build := func() Table {
+		table := New("Host", "Port", "Requests")
+		table.AddRow("").Insert("web1", 80, 10)
+		table.AddRow("").Insert("web1", 443, 20)
+		table.AddRow("").Insert("web2", 80, Missing)
+		return table
+	}
+
+	refresh := New("Port", "Host", "Requests", "Errors")
+	refresh.AddRow("").Insert(443, "web1", 5, 1)
+	refresh.AddRow("").Insert(80, "web2", 7, 0)
+	refresh.AddRow("web3").Insert(80, "web3", 1, 0)
+
+	table := build()
+	if err := table.MergeFrom(refresh, MergeOptions{}, "Region"); err == nil {
+		t.Errorf("TestMergeFrom: unknown key columns should fail")
+	}
+
+	// Columns absent in the table are added, rows without matches appended
+	tests := []struct {
+		resolve  Resolver
+		requests []interface{}
+		errors   []interface{}
+	}{
+		{nil, []interface{}{10, 5, 7, 1}, []interface{}{Missing, 1, 0, 0}},
+		{PreferCurrent, []interface{}{10, 20, 7, 1}, []interface{}{Missing, 1, 0, 0}},
+		{Combine(Sum), []interface{}{10, 25.0, 7.0, 1}, []interface{}{Missing, 1.0, 0.0, 0}},
+	}
+
+	for _, test := range tests {
+		table := build()
+		if err := table.MergeFrom(refresh, MergeOptions{Resolve: test.resolve}, "Host", "Port"); err != nil {
+			t.Fatalf("TestMergeFrom: could not merge: %s", err.Error())
+		}
+		checkValues(t, "TestMergeFrom", table, "Requests", test.requests...)
+		checkValues(t, "TestMergeFrom", table, "Errors", test.errors...)
+	}
+
+	if names := table.GetRowNames(); len(names) != 4 {
+		t.Errorf("TestMergeFrom: failed merges should not change the table: %v", names)
+	}
+}
+
+func TestUpsertKeyTypes(t *testing.T) {
+
+	table := New("id", "value")
+	table.AddRow("").Insert(1, "int-one")
+
+	// Equally printed keys of other types are not matches
+	if _, err := table.Upsert("id", "1", "string-one"); err != nil {
+		t.Fatalf("TestUpsertKeyTypes: could not upsert: %s", err.Error())
+	}
+	if count := table.GetRowCount(); count != 3 {
+		t.Errorf("TestUpsertKeyTypes: expected 3 rows, got %d", count)
+	}
+
+	ints, _ := table.FindRows("value", "int-one")
+	strs, _ := table.FindRows("id", "1")
+	if len(ints) != 1 || len(strs) != 1 {
+		t.Errorf("TestUpsertKeyTypes: the int key should not be overwritten")
+	}
+}
+
+func TestUpsertConcurrently(t *testing.T) {
+
+	table := New("Host", "Load")
+	web1 := table.AddRow("").Insert("web1", 0)
+
+	// Upserting (table, then row) must not deadlock changing (row, then table)
+	done := make(chan bool)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	go func() {
+		for i := 0; time.Now().Before(deadline); i++ {
+			table.Upsert("Host", "web1", i)
+		}
+		done <- true
+	}()
+	go func() {
+		for i := 0; time.Now().Before(deadline); i++ {
+			web1.Change("Load", -i)
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("TestUpsertConcurrently: deadlock")
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// ColumnContext describes the column a CellRenderer is applied to. The range
+// of values is calculated at render time from all the numeric values of the
+// column's body rows (including the elements of slices).
+type ColumnContext struct {
+	Name     string
+	Min, Max float64
+	Width    int          // Column width override (SetColumnWidth), 0 if not set
+	Profile  ColorProfile // Color profile of the template's theme
+}
+
+// CellRenderer visualizes a raw cell value in the context of its column.
+// formatted contains the formatted and modified value of the cell.
+//
+// Cell renderers are only used in the modified view of a table
+// (Render(..., modified=true, ...)) and their output (excluding ANSI escape
+// sequences) is always used to calculate cell widths.
+type CellRenderer func(v interface{}, formatted string, column ColumnContext) string
+
+// Block elements used by the renderers
+var (
+	sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+	barBlocks   = []rune("▏▎▍▌▋▊▉█")
+)
+
+// BarRenderer renders numeric values as horizontal bars scaled to the column's
+// maximum (absolute) value. The bar is at most width characters wide, unless
+// the column width has been overridden.
+func BarRenderer(width int) CellRenderer {
+	return func(v interface{}, formatted string, column ColumnContext) string {
+
+		f, ok := toFloat(v)
+		if !ok {
+			return formatted
+		}
+
+		barWidth := width
+		if column.Width > 0 {
+			barWidth = column.Width
+		}
+
+		scale := math.Max(math.Abs(column.Min), math.Abs(column.Max))
+		if scale == 0 || barWidth <= 0 {
+			return ""
+		}
+
+		// Whole and partial blocks
+		eighths := int(math.Abs(f) / scale * float64(barWidth*8))
+		bar := strings.Repeat(string(barBlocks[7]), eighths/8)
+		if rest := eighths % 8; rest > 0 {
+			bar += string(barBlocks[rest-1])
+		}
+
+		return bar
+	}
+}
+
+// SparklineRenderer renders numeric slices as sparklines (▁▂▃▅▇) scaled to
+// the range of the whole column. If the column width has been overridden,
+// only the last values fitting into the column are shown.
+func SparklineRenderer() CellRenderer {
+	return func(v interface{}, formatted string, column ColumnContext) string {
+
+		values := []float64{}
+		for _, element := range flatten(v) {
+			if f, ok := toFloat(element); ok {
+				values = append(values, f)
+			}
+		}
+		if len(values) == 0 {
+			return formatted
+		}
+
+		if column.Width > 0 && len(values) > column.Width {
+			values = values[len(values)-column.Width:]
+		}
+
+		spread := column.Max - column.Min
+		spark := make([]rune, len(values))
+		for i, f := range values {
+			level := len(sparkBlocks) - 1
+			if spread > 0 {
+				level = int((f - column.Min) / spread * float64(len(sparkBlocks)-1))
+			}
+			spark[i] = sparkBlocks[clampInt(level, 0, len(sparkBlocks)-1)]
+		}
+
+		return string(spark)
+	}
+}
+
+// ProgressRenderer renders ratios (0..1) as progress bars followed by the
+// percentage, e.g. "██████░░░░  60%". The bar is width characters wide, unless
+// the column width has been overridden.
+func ProgressRenderer(width int) CellRenderer {
+	return func(v interface{}, formatted string, column ColumnContext) string {
+
+		f, ok := toFloat(v)
+		if !ok {
+			return formatted
+		}
+
+		barWidth := width
+		if column.Width > 0 {
+			barWidth = column.Width - 5
+		}
+		if barWidth < 1 {
+			barWidth = 1
+		}
+
+		ratio := math.Min(math.Max(f, 0), 1)
+		done := int(ratio*float64(barWidth) + 0.5)
+
+		return fmt.Sprintf("%s%s %3.0f%%", strings.Repeat("█", done), strings.Repeat("░", barWidth-done), ratio*100)
+	}
+}
+
+// HeatmapRenderer colors the background of numeric cells by interpolating
+// between low and high across the column's range of values
+func HeatmapRenderer(low, high Color) CellRenderer {
+	return func(v interface{}, formatted string, column ColumnContext) string {
+
+		f, ok := toFloat(v)
+		if !ok {
+			return formatted
+		}
+
+		ratio := 1.0
+		if spread := column.Max - column.Min; spread > 0 {
+			ratio = (f - column.Min) / spread
+		}
+
+		return Style{Bg: interpolate(low, high, ratio)}.Sprint(formatted, column.Profile)
+	}
+}
+
+// SetCellRenderer visualizes the values of columns using a cell renderer
+// (see BarRenderer, SparklineRenderer, ProgressRenderer, HeatmapRenderer).
+// Setting a nil renderer removes the visualization.
+// NB: locks t
+func (t *table) SetCellRenderer(renderer CellRenderer, colnames ...string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(colnames) == 0 {
+		return fmt.Errorf("SetCellRenderer: provide at least one column name")
+	}
+
+	colIdx := t.getColIdx(colnames...)
+	if len(colIdx) == 0 {
+		return fmt.Errorf("SetCellRenderer: no such columns")
+	}
+
+	for _, idx := range colIdx {
+		if renderer == nil {
+			delete(t.renderers, idx)
+		} else {
+			t.renderers[idx] = renderer
+		}
+	}
+
+	return nil
+}
+
+// columnContexts calculates the contexts of all the columns having a cell
+// renderer
+func (t *table) columnContexts(profile ColorProfile) map[int]ColumnContext {
+
+	contexts := map[int]ColumnContext{}
+	if len(t.renderers) == 0 {
+		return contexts
+	}
+
+	header := t.headAndFoot["header"]
+	footer := t.headAndFoot["footer"]
+
+	for col := range t.renderers {
+		context := ColumnContext{
+			Min:     math.Inf(1),
+			Max:     math.Inf(-1),
+			Width:   t.WidthOverrides[col],
+			Profile: profile,
+		}
+		if header != nil && col < len(header.Cells) {
+			context.Name = fmt.Sprintf("%v", header.Cells[col].Value)
+		}
+
+		for _, row := range t.Rows {
+			if row == header || row == footer || col >= len(row.Cells) {
+				continue
+			}
+			for _, element := range flatten(row.Cells[col].Value) {
+				if f, ok := toFloat(element); ok {
+					context.Min = math.Min(context.Min, f)
+					context.Max = math.Max(context.Max, f)
+				}
+			}
+		}
+
+		if math.IsInf(context.Min, 0) {
+			context.Min, context.Max = 0, 0
+		}
+
+		contexts[col] = context
+	}
+
+	return contexts
+}
+
+// flatten returns the elements of a slice or the value itself
+func flatten(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+
+	elements := make([]interface{}, value.Len())
+	for i := range elements {
+		elements[i] = value.Index(i).Interface()
+	}
+
+	return elements
+}
+
+// interpolate returns a 24-bit color between a and b
+func interpolate(a, b Color, ratio float64) Color {
+	ca, cb := a.rgb(), b.rgb()
+
+	channel := func(shift uint) uint8 {
+		x, y := float64(ca>>shift&0xff), float64(cb>>shift&0xff)
+		return uint8(x + (y-x)*math.Min(math.Max(ratio, 0), 1) + 0.5)
+	}
+
+	return RGB(channel(16), channel(8), channel(0))
+}
+
+// rgb returns the 24-bit representation of a color
+func (c Color) rgb() uint32 {
+	switch c.Mode {
+	case Color16, Color256:
+		return xtermToRGB(int(c.Value))
+	case ColorRGB:
+		return c.Value
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,75 @@
+package lentele
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCellRenderers(t *testing.T) {
+
+	context := ColumnContext{Min: -2, Max: 4, Profile: ProfileTrueColor}
+
+	tests := []struct {
+		renderer CellRenderer
+		value    interface{}
+		context  ColumnContext
+		expected string
+	}{
+		{BarRenderer(4), 4, context, "████"},
+		{BarRenderer(4), 1.5, context, "█▌"},
+		{BarRenderer(4), -2, context, "██"},
+		{BarRenderer(4), 2, ColumnContext{Min: 0, Max: 4, Width: 8}, "████"},
+		{BarRenderer(4), "n/a", context, "n/a"},
+		{SparklineRenderer(), []float64{-2, 1, 4}, context, "▁▄█"},
+		{SparklineRenderer(), []int{-2, 1, 4}, ColumnContext{Min: -2, Max: 4, Width: 2}, "▄█"},
+		{ProgressRenderer(10), 0.5, context, "█████░░░░░  50%"},
+		{ProgressRenderer(10), 1.2, ColumnContext{Width: 9}, "████ 100%"},
+		{HeatmapRenderer(RGB(0, 0, 0), RGB(200, 100, 0)), 1, context, "\033[48;2;100;50;0mn/a\033[0m"},
+	}
+
+	for i, test := range tests {
+		if rendered := test.renderer(test.value, "n/a", test.context); rendered != test.expected {
+			t.Errorf("TestCellRenderers: test %d failed: expected %q, got %q", i+1, test.expected, rendered)
+		}
+	}
+}
+
+func TestSetCellRenderer(t *testing.T) {
+
+	table := New("Host", "CPU", "Load")
+	table.AddRow("").Insert("alpha", 0.25, []float64{1, 2, 3})
+	table.AddRow("").Insert("beta", 0.75, []float64{3, 2, 1})
+	table.AddFooter().Insert("Total", 1.0, "-")
+
+	if err := table.SetCellRenderer(ProgressRenderer(4), "CPU"); err != nil {
+		t.Errorf("TestSetCellRenderer: could not set renderer: %s", err.Error())
+	}
+	if err := table.SetCellRenderer(SparklineRenderer(), "Load"); err != nil {
+		t.Errorf("TestSetCellRenderer: could not set renderer: %s", err.Error())
+	}
+	if err := table.SetCellRenderer(SparklineRenderer(), "Memory"); err == nil {
+		t.Errorf("TestSetCellRenderer: unknown columns should fail")
+	}
+
+	out := bytes.NewBuffer([]byte{})
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+
+	expected := []string{
+		"| alpha | █░░░  25% | ▁▄█  |",
+		"| beta  | ███░  75% | █▄▁  |",
+		"| Total |     1     |  -   |",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("TestSetCellRenderer: expected line %q in:\n%s", line, out.String())
+		}
+	}
+
+	table.SetCellRenderer(nil, "CPU", "Load")
+	out.Reset()
+	table.Render(out, false, true, false, MustLoadTemplate("mysql"))
+	if strings.Contains(out.String(), "█") {
+		t.Errorf("TestSetCellRenderer: removed renderers should not be used")
+	}
+}
@@ -0,0 +1,327 @@
+package lentele
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Window maps the values of a column (in the order of the rows) to the values
+// of a derived column. Values that cannot be calculated are Missing.
+type Window func(values []interface{}) []interface{}
+
+// AddWindowColumn adds a column (name) calculated by the window from the values
+// of another column in the current order of the body rows. If a partition
+// column is provided, the window is applied to the rows of every value of the
+// partition column separately. The header and the footer are skipped.
+// NB: locks t, then the header and the body rows
+func (t *table) AddWindowColumn(name, column, partition string, window Window) error {
+
+	writes, err := t.windowColumn(name, column, partition, window)
+	if err != nil {
+		return fmt.Errorf("AddWindowColumn: %s", err.Error())
+	}
+	writeCells(writes)
+
+	return nil
+}
+
+// windowColumn returns the header and the body row values of the window
+// column
+// NB: locks t
+func (t *table) windowColumn(name, column, partition string, window Window) ([]cellWrite, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	header, ok := t.headAndFoot["header"]
+	if !ok {
+		return nil, fmt.Errorf("table has no header")
+	}
+
+	if window == nil {
+		return nil, fmt.Errorf("window cannot be nil")
+	}
+
+	if t.getColnameIndex(name, false, false) != -1 {
+		return nil, fmt.Errorf("column '%s' already exists", name)
+	}
+
+	colIdx := t.getColIdx(column)
+	if len(colIdx) == 0 {
+		return nil, fmt.Errorf("no such column '%s'", column)
+	}
+	col := colIdx[0]
+
+	partIdx := -1
+	if partition != "" {
+		idx := t.getColIdx(partition)
+		if len(idx) == 0 {
+			return nil, fmt.Errorf("no such column '%s'", partition)
+		}
+		partIdx = idx[0]
+	}
+
+	// Partition the body rows in the order of their first appearance
+	partitions := [][]*row{}
+	index := map[string]int{}
+	for _, bodyRow := range t.bodyRows() {
+		key := ""
+		if partIdx != -1 {
+			key = fmt.Sprintf("%v", cellValue(bodyRow, partIdx))
+		}
+		if _, ok := index[key]; !ok {
+			index[key] = len(partitions)
+			partitions = append(partitions, []*row{})
+		}
+		partitions[index[key]] = append(partitions[index[key]], bodyRow)
+	}
+
+	// Add the column (the header is written with the body rows)
+	position := t.columnCount()
+	writes := []cellWrite{{header, position, name, false}}
+	for _, rows := range partitions {
+
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = cellValue(row, col)
+		}
+
+		results := window(values)
+		for i, row := range rows {
+			result := interface{}(Missing)
+			if i < len(results) {
+				result = results[i]
+			}
+			writes = append(writes, cellWrite{row, position, result, false})
+		}
+	}
+
+	return writes, nil
+}
+
+// cellValue returns the value of the row's column (Missing if the row is short
+// or nil)
+// NB: locks the cell
+func cellValue(r *row, col int) interface{} {
+	if r != nil && col < len(r.Cells) {
+		c := r.Cells[col]
+		c.Lock()
+		defer c.Unlock()
+		return c.Value
+	}
+	return Missing
+}
+
+// cellWrite is a value written into a row's cell (see writeCells)
+type cellWrite struct {
+	r     *row
+	col   int
+	value interface{}
+	fill  bool // Only written if the cell is (still) missing
+}
+
+// writeCells writes the values into the cells of the rows. The row methods
+// lock the row before its table, hence the table must not be locked by the
+// caller.
+// NB: locks the rows
+func writeCells(writes []cellWrite) {
+	for _, w := range writes {
+		w.r.Lock()
+		if !w.fill || isMissing(cellValue(w.r, w.col)) {
+			setCell(w.r, w.col, w.value)
+		}
+		w.r.Unlock()
+	}
+}
+
+// setCell sets the value of the row's column, padding short rows with missing
+// values. The row has to be locked by the caller, or belong to a table nobody
+// else has access to yet.
+func setCell(r *row, col int, value interface{}) {
+	for len(r.Cells) <= col {
+		r.Cells = append(r.Cells, &cell{Mutex: &sync.Mutex{}, Value: Missing})
+	}
+
+	r.Cells[col].Lock()
+	r.Cells[col].Value = value
+	r.Cells[col].Unlock()
+	r.touch()
+	r.tref.touch()
+}
+
+// Rolling aggregates the values of a moving window of the current and the
+// preceding rows, e.g. Rolling(3, Mean). The first size-1 values are missing.
+func Rolling(size int, aggregate Aggregate) Window {
+	return func(values []interface{}) []interface{} {
+		results := missingValues(len(values))
+		for i := size - 1; size > 0 && i < len(values); i++ {
+			results[i] = aggregate(values[i-size+1 : i+1])
+		}
+		return results
+	}
+}
+
+// CumSum returns the cumulative sums of the numeric values
+func CumSum(values []interface{}) []interface{} {
+	return cumulate(values, 0, func(a, b float64) float64 { return a + b })
+}
+
+// CumProd returns the cumulative products of the numeric values
+func CumProd(values []interface{}) []interface{} {
+	return cumulate(values, 1, func(a, b float64) float64 { return a * b })
+}
+
+// cumulate accumulates the numeric values (other values are missing)
+func cumulate(values []interface{}, start float64, op func(a, b float64) float64) []interface{} {
+	results := missingValues(len(values))
+	for i, v := range values {
+		if f, ok := toFloat(v); ok {
+			start = op(start, f)
+			results[i] = start
+		}
+	}
+	return results
+}
+
+// Lag shifts the values forward by n rows, i.e. every row gets the value of
+// the n-th preceding row
+func Lag(n int) Window {
+	return func(values []interface{}) []interface{} {
+		results := missingValues(len(values))
+		for i := range values {
+			if i-n >= 0 && i-n < len(values) {
+				results[i] = values[i-n]
+			}
+		}
+		return results
+	}
+}
+
+// Lead shifts the values backward by n rows, i.e. every row gets the value of
+// the n-th following row
+func Lead(n int) Window {
+	return Lag(-n)
+}
+
+// Diff returns the differences between the values and the values n rows
+// before
+func Diff(n int) Window {
+	return compare(n, func(current, previous float64) (float64, bool) {
+		return current - previous, true
+	})
+}
+
+// PctChange returns the relative changes of the values compared to the values
+// n rows before, e.g. 0.1 for a 10% increase
+func PctChange(n int) Window {
+	return compare(n, func(current, previous float64) (float64, bool) {
+		if previous == 0 {
+			return 0, false
+		}
+		return current/previous - 1, true
+	})
+}
+
+// compare compares the numeric values with the values n rows before
+func compare(n int, op func(current, previous float64) (float64, bool)) Window {
+	return func(values []interface{}) []interface{} {
+		lagged := Lag(n)(values)
+		results := missingValues(len(values))
+		for i := range values {
+			current, ok := toFloat(values[i])
+			if !ok {
+				continue
+			}
+			previous, ok := toFloat(lagged[i])
+			if !ok {
+				continue
+			}
+			if result, ok := op(current, previous); ok {
+				results[i] = result
+			}
+		}
+		return results
+	}
+}
+
+// Rank ranks the numeric values (1 = smallest or, if descending, largest).
+// Ties get the same rank and leave gaps, e.g. 1, 2, 2, 4.
+func Rank(descending bool) Window {
+	return rank(descending, func(position, distinct int) interface{} {
+		return position
+	})
+}
+
+// DenseRank is same as Rank, only without gaps, e.g. 1, 2, 2, 3
+func DenseRank(descending bool) Window {
+	return rank(descending, func(position, distinct int) interface{} {
+		return distinct
+	})
+}
+
+// Percentile returns the ranks of the numeric values divided by the count of
+// numeric values, e.g. 0.25, 0.5, 0.75, 1
+func Percentile(descending bool) Window {
+	return func(values []interface{}) []interface{} {
+		ranks := Rank(descending)(values)
+
+		count := 0
+		for _, v := range ranks {
+			if !isMissing(v) {
+				count++
+			}
+		}
+
+		for i, v := range ranks {
+			if position, ok := v.(int); ok {
+				ranks[i] = float64(position) / float64(count)
+			}
+		}
+		return ranks
+	}
+}
+
+// rank sorts the numeric values and assigns ranks (other values are missing)
+func rank(descending bool, assign func(position, distinct int) interface{}) Window {
+	return func(values []interface{}) []interface{} {
+
+		type ranked struct {
+			index int
+			value float64
+		}
+
+		numeric := []ranked{}
+		for i, v := range values {
+			if f, ok := toFloat(v); ok && !math.IsNaN(f) {
+				numeric = append(numeric, ranked{i, f})
+			}
+		}
+
+		sort.SliceStable(numeric, func(a, b int) bool {
+			if descending {
+				return numeric[a].value > numeric[b].value
+			}
+			return numeric[a].value < numeric[b].value
+		})
+
+		results := missingValues(len(values))
+		position, distinct := 0, 0
+		for k, r := range numeric {
+			if k == 0 || r.value != numeric[k-1].value {
+				position, distinct = k+1, distinct+1
+			}
+			results[r.index] = assign(position, distinct)
+		}
+		return results
+	}
+}
+
+// missingValues returns a slice of missing values
+func missingValues(n int) []interface{} {
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = Missing
+	}
+	return values
+}
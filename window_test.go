@@ -0,0 +1,96 @@
+package lentele
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWindows(t *testing.T) {
+
+	values := []interface{}{1, 3, "x", 5, 5, 2}
+	m := Missing
+
+	tests := []struct {
+		window   Window
+		expected []interface{}
+	}{
+		{Rolling(2, Sum), []interface{}{m, 4.0, 3.0, 5.0, 10.0, 7.0}},
+		{CumSum, []interface{}{1.0, 4.0, m, 9.0, 14.0, 16.0}},
+		{CumProd, []interface{}{1.0, 3.0, m, 15.0, 75.0, 150.0}},
+		{Lag(1), []interface{}{m, 1, 3, "x", 5, 5}},
+		{Lead(2), []interface{}{"x", 5, 5, 2, m, m}},
+		{Diff(1), []interface{}{m, 2.0, m, m, 0.0, -3.0}},
+		{PctChange(1), []interface{}{m, 2.0, m, m, 0.0, -0.6}},
+		{Rank(false), []interface{}{1, 3, m, 4, 4, 2}},
+		{Rank(true), []interface{}{5, 3, m, 1, 1, 4}},
+		{DenseRank(true), []interface{}{4, 2, m, 1, 1, 3}},
+		{Percentile(false), []interface{}{0.2, 0.6, m, 0.8, 0.8, 0.4}},
+	}
+
+	for i, test := range tests {
+		if results := test.window(values); !reflect.DeepEqual(results, test.expected) {
+			t.Errorf("TestWindows: test %d failed: expected %v, got %v", i+1, test.expected, results)
+		}
+	}
+}
+
+func TestAddWindowColumn(t *testing.T) {
+
+	table := New("Country", "Year", "GDP")
+	table.AddRow("").Insert("LT", 2014, 3.5)
+	table.AddRow("").Insert("LV", 2014, 1.9)
+	table.AddRow("").Insert("LT", 2015, 2.0)
+	table.AddRow("").Insert("LV", 2015, 3.0)
+	table.AddFooter()
+
+	if err := table.AddWindowColumn("Change", "Population", "", Diff(1)); err == nil {
+		t.Errorf("TestAddWindowColumn: unknown columns should fail")
+	}
+	if err := table.AddWindowColumn("GDP", "GDP", "", Diff(1)); err == nil {
+		t.Errorf("TestAddWindowColumn: existing columns should fail")
+	}
+
+	// Differences within the countries, the footer is left out
+	table.AddWindowColumn("Change", "GDP", "Country", Diff(1))
+	lt, lv := 3.5, 1.9
+	checkValues(t, "TestAddWindowColumn", table, "Change", Missing, Missing, 2.0-lt, 3.0-lv)
+
+	// Formatters apply to the new column
+	table.SetFormatter(MustLoadLocale("en-US").Number(1), "Change")
+	if rendered := renderMySQL(table); !strings.Contains(rendered, "|   LV    | 2015 |  3  |  1.1   |") {
+		t.Errorf("TestAddWindowColumn: unexpected output:\n%s", rendered)
+	}
+}
+
+func TestAddWindowColumnConcurrently(t *testing.T) {
+
+	table := New("a", "b")
+	changed := table.AddRow("").Insert(1, 2)
+
+	// Adding columns (table, then row) must not deadlock changing (row, then table)
+	done := make(chan bool)
+	deadline := time.Now().Add(200 * time.Millisecond)
+	go func() {
+		for i := 0; i < 1000 && time.Now().Before(deadline); i++ {
+			table.AddWindowColumn(fmt.Sprintf("lag%d", i), "b", "", Lag(1))
+		}
+		done <- true
+	}()
+	go func() {
+		for i := 0; time.Now().Before(deadline); i++ {
+			changed.Change("b", i)
+		}
+		done <- true
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("TestAddWindowColumnConcurrently: deadlock")
+		}
+	}
+}